@@ -0,0 +1,201 @@
+package neo
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// allowEntry lists the HTTP methods allowed for the paths matching a route path.
+type allowEntry struct {
+	methods map[string]bool // the methods of the routes matching the paths
+	header  string          // the value of the Allow header, including the implicit HEAD and OPTIONS methods
+	route   *Route          // the first route added with the path, whose group responds to OPTIONS requests
+	// the methods of the routes with other path patterns that match some of the paths. They are checked
+	// against the request path when the entry is used.
+	candidates []string
+}
+
+// pathIndexes holds the indexes of the route paths built for each host (nil for the routes matching any host).
+type pathIndexes struct {
+	allow map[*hostRoutes]*store // maps the route paths to their allowEntry
+	fold  map[*hostRoutes]*store // holds the route paths matched regardless of their case
+}
+
+// allowedMethods returns the allowEntry of the routes matching the host and the path, regardless of
+// their methods. Nil is returned if no route matches the path. The pvalues buffer, whose length must be
+// at least the maximum number of parameters, is overwritten.
+//
+// The allowed methods are computed once per route path, when the first request needs them after
+// the routes have changed or when the router is frozen, and looked up without locking afterwards.
+func (r *Router) allowedMethods(host, path string, pvalues []string) *allowEntry {
+	indexes := r.pathIndexes()
+	if len(r.hosts) > 0 && host != "" {
+		host = stripHostPort(host)
+		for _, h := range r.hosts {
+			if !h.regex.MatchString(host) {
+				continue
+			}
+			if data, _ := indexes.allow[h].Get(path, pvalues); data != nil {
+				return r.resolveCandidates(data.(*allowEntry), h.stores, path, pvalues)
+			}
+		}
+	}
+	if data, _ := indexes.allow[nil].Get(path, pvalues); data != nil {
+		return r.resolveCandidates(data.(*allowEntry), r.stores, path, pvalues)
+	}
+	return nil
+}
+
+// findAllowed returns the allowEntry like allowedMethods, taking the read lock of the router and borrowing
+// the parameter buffer of a pooled context. It is used outside of the request routing.
+func (r *Router) findAllowed(host, path string) *allowEntry {
+	c := r.pool.Get().(*Context)
+	r.mu.RLock()
+	if len(c.pvalues) < r.maxParams {
+		c.pvalues = make([]string, r.maxParams)
+	}
+	allow := r.allowedMethods(host, path, c.pvalues)
+	r.mu.RUnlock()
+	r.pool.Put(c)
+	return allow
+}
+
+// pathIndexes returns the path indexes of the current routes, building them if needed.
+// The caller must hold the read lock of the router unless the router is frozen.
+func (r *Router) pathIndexes() *pathIndexes {
+	if indexes, _ := r.indexes.Load().(*pathIndexes); indexes != nil {
+		return indexes
+	}
+	r.allowMu.Lock()
+	defer r.allowMu.Unlock()
+	if indexes, _ := r.indexes.Load().(*pathIndexes); indexes != nil {
+		// built by a concurrent request
+		return indexes
+	}
+	indexes := &pathIndexes{
+		allow: make(map[*hostRoutes]*store, len(r.hosts)+1),
+		fold:  make(map[*hostRoutes]*store, len(r.hosts)+1),
+	}
+	for _, h := range append([]*hostRoutes{nil}, r.hosts...) {
+		indexes.allow[h] = r.allowIndex(h)
+		indexes.fold[h] = r.foldIndex(h)
+	}
+	r.indexes.Store(indexes)
+	return indexes
+}
+
+// resolveCandidates returns the allowEntry for the path, including the candidate methods whose routes match it.
+func (r *Router) resolveCandidates(entry *allowEntry, stores map[string]routeStore, path string, pvalues []string) *allowEntry {
+	if len(entry.candidates) == 0 {
+		return entry
+	}
+	methods := make(map[string]bool, len(entry.methods)+len(entry.candidates))
+	for method := range entry.methods {
+		methods[method] = true
+	}
+	for _, method := range entry.candidates {
+		if data, _ := stores[method].Get(path, pvalues); data != nil {
+			methods[method] = true
+		}
+	}
+	return &allowEntry{methods: methods, header: r.allowHeader(methods), route: entry.route}
+}
+
+// allowIndex builds the store mapping the route paths of the given host (nil for the routes matching
+// any host) to their allowEntry.
+//
+// A route path is allowed the methods of the routes with the same path. For a static path, the methods of
+// the routes with other path patterns matching it are allowed as well. For a parametric path, such methods,
+// which are detected with sample paths like Validate does, are candidates checked against each request path.
+// Static paths are added to the index first so that they take precedence over parametric paths matching
+// the same request paths.
+func (r *Router) allowIndex(host *hostRoutes) *store {
+	stores := r.stores
+	if host != nil {
+		stores = host.stores
+	}
+
+	var static, parametric []string
+	pathMethods := map[string]map[string]bool{} // the methods of the routes with each path
+	pathRoutes := map[string]*Route{}           // the first route with each path
+	for _, route := range r.routes {
+		if route.group.host != host {
+			continue
+		}
+		for _, sp := range route.storePaths() {
+			if pathMethods[sp.path] == nil {
+				pathMethods[sp.path] = map[string]bool{}
+				pathRoutes[sp.path] = route
+				if strings.Contains(sp.path, "<") {
+					parametric = append(parametric, sp.path)
+				} else {
+					static = append(static, sp.path)
+				}
+			}
+			pathMethods[sp.path][route.method] = true
+		}
+	}
+
+	index := newStore()
+	pvalues := make([]string, r.maxParams)
+	for _, path := range append(static, parametric...) {
+		samples := []string{path}
+		if strings.Contains(path, "<") {
+			samples = samplePaths(path)
+		}
+		entry := &allowEntry{methods: pathMethods[path], route: pathRoutes[path]}
+		for method, s := range stores {
+			if entry.methods[method] {
+				continue
+			}
+			for _, sample := range samples {
+				if data, _ := s.Get(sample, pvalues); data != nil {
+					if sample == path {
+						// a static path is its only sample
+						entry.methods[method] = true
+					} else {
+						entry.candidates = append(entry.candidates, method)
+					}
+					break
+				}
+			}
+		}
+		entry.header = r.allowHeader(entry.methods)
+		index.Add(path, entry)
+	}
+	return index
+}
+
+// allowHeader returns the value of the Allow header listing the given methods, the OPTIONS method,
+// and the HEAD method if GET is allowed and HandleHead is enabled.
+func (r *Router) allowHeader(methods map[string]bool) string {
+	ms := []string{"OPTIONS"}
+	for method := range methods {
+		if method != "OPTIONS" {
+			ms = append(ms, method)
+		}
+	}
+	if methods["GET"] && !methods["HEAD"] && r.HandleHead {
+		ms = append(ms, "HEAD")
+	}
+	sort.Strings(ms)
+	return strings.Join(ms, ", ")
+}
+
+// resetAllowIndexes discards the allowed methods and the case-folding indexes computed for the previous routes.
+// The caller must hold the write lock of the router.
+func (r *Router) resetAllowIndexes() {
+	r.indexes.Store((*pathIndexes)(nil))
+}
+
+// methodNotAllowed responds to a request whose path matches some routes, none of which has the request method.
+// It sets the Allow header, and responds with http.StatusMethodNotAllowed unless the request is an OPTIONS request.
+func methodNotAllowed(c *Context) error {
+	c.Response.Header().Set("Allow", c.allow.header)
+	if c.Request.Method == "OPTIONS" {
+		c.Abort()
+		return nil
+	}
+	return NewHTTPError(http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,109 @@
+package neo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterHandleHead(t *testing.T) {
+	router := New()
+	router.Get("/users", func(c *Context) error {
+		c.Response.Header().Set("X-Total", "2")
+		return c.Write("users")
+	})
+	router.Get("/items", func(c *Context) error { return c.Write("items") })
+	router.Head("/items", func(c *Context) error {
+		c.Response.Header().Set("X-Head", "explicit")
+		return nil
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("HEAD", "/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "2", res.Header().Get("X-Total"))
+	assert.Equal(t, "", res.Body.String())
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("HEAD", "/items", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "explicit", res.Header().Get("X-Head"))
+
+	router.HandleHead = false
+	router.Get("/other", func(c *Context) error { return nil })
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("HEAD", "/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "GET, OPTIONS", res.Header().Get("Allow"))
+}
+
+func TestRouterHandleMethodNotAllowed(t *testing.T) {
+	router := New()
+	h := func(c *Context) error { return c.Write(c.Request.Method) }
+	router.Get("/users/<id>", h)
+	router.Post("/users/new", h)
+	router.Delete("/users/<id:\\d+>", h)
+	router.Host("api.example.com").Put("/users/<id>", h)
+
+	tests := []struct {
+		method, url string
+		status      int
+		allow       string
+	}{
+		{"PUT", "/users/new", http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS, POST"},
+		{"PUT", "/users/abc", http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS"},
+		{"PUT", "/users/12", http.StatusMethodNotAllowed, "DELETE, GET, HEAD, OPTIONS"},
+		{"OPTIONS", "/users/abc", http.StatusOK, "GET, HEAD, OPTIONS"},
+		{"PATCH", "http://api.example.com/users/1", http.StatusMethodNotAllowed, "OPTIONS, PUT"},
+		{"PATCH", "http://api.example.com/orders", http.StatusNotFound, ""},
+		{"GET", "/orders", http.StatusNotFound, ""},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.status, res.Code, test.method+" "+test.url)
+		assert.Equal(t, test.allow, res.Header().Get("Allow"), test.method+" "+test.url)
+	}
+	assert.Equal(t, map[string]bool{"GET": true, "POST": true}, router.FindAllowedMethods("/users/new"))
+
+	// the allowed methods are recomputed after the routes change
+	router.Put("/users/<id>", h)
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/users/abc", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "GET, HEAD, OPTIONS, PUT", res.Header().Get("Allow"))
+
+	router.HandleMethodNotAllowed = false
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	router.NotFound(MethodNotAllowedHandler, NotFoundHandler)
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, PUT", res.Header().Get("Allow"))
+}
+
+func TestRouterAllowedMethodsFrozen(t *testing.T) {
+	router := New()
+	h := func(c *Context) error { return nil }
+	router.Get("/users/<id>", h)
+	router.Post("/users", h)
+	router.Freeze()
+
+	pvalues := make([]string, router.maxParams)
+	for _, path := range []string{"/users", "/users/12"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			router.allowedMethods("", path, pvalues)
+		})
+		assert.Zero(t, allocs, path)
+	}
+	assert.Equal(t, "GET, HEAD, OPTIONS", router.allowedMethods("", "/users/12", pvalues).header)
+	assert.Nil(t, router.allowedMethods("", "/orders", pvalues))
+}
@@ -0,0 +1,156 @@
+package neo
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// cleanPath returns the canonical form of a request path: it removes duplicate slashes and resolves "." and ".."
+// segments like path.Clean, except that it keeps the trailing slash and always returns a path starting with "/".
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] == '/' && !strings.Contains(p, "//") && !strings.Contains(p, "/.") {
+		// the path is clean already: avoid allocating
+		return p
+	}
+	cleaned := path.Clean("/" + p)
+	if p[len(p)-1] == '/' && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// foldPath returns the path of the routes matching the host and the path regardless of the case of the letters
+// in their static parts, with these parts replaced by their registered form. An empty string is returned if no
+// route matches the path. The path of a route whose static parts match exactly is preferred.
+func (r *Router) foldPath(host, path string) string {
+	indexes := r.pathIndexes()
+	buf := make([]byte, 0, len(path))
+	if len(r.hosts) > 0 && host != "" {
+		host = stripHostPort(host)
+		for _, h := range r.hosts {
+			if !h.regex.MatchString(host) {
+				continue
+			}
+			if b, ok := indexes.fold[h].root.fold(path, buf); ok {
+				return string(b)
+			}
+		}
+	}
+	if b, ok := indexes.fold[nil].root.fold(path, buf); ok {
+		return string(b)
+	}
+	return ""
+}
+
+// foldIndex builds the store holding the route paths of the given host (nil for the routes matching any host),
+// regardless of the route methods.
+func (r *Router) foldIndex(host *hostRoutes) *store {
+	index := newStore()
+	for _, route := range r.routes {
+		if route.group.host != host {
+			continue
+		}
+		for _, sp := range route.storePaths() {
+			// a path added again is ignored by the store
+			index.Add(sp.path, true)
+		}
+	}
+	return index
+}
+
+// fold matches the key against the tree rooted at the current node, comparing the ASCII letters of the static
+// nodes regardless of their case, and appends the matching path, with the static parts as registered, to buf.
+// A static child whose first byte has the same case as the key is tried first.
+func (n *node) fold(key string, buf []byte) ([]byte, bool) {
+	if n.static {
+		nkl := len(n.key)
+		if nkl > len(key) || !equalFoldASCII(n.key, key[:nkl]) {
+			return buf, false
+		}
+		buf = append(buf, n.key...)
+		key = key[nkl:]
+	} else {
+		i := strings.IndexByte(key, '/')
+		if i < 0 {
+			i = len(key)
+		}
+		if n.regex != nil {
+			if match := n.regex.FindStringIndex(key); match != nil {
+				i = match[1]
+			} else {
+				return buf, false
+			}
+		}
+		buf = append(buf, key[:i]...)
+		key = key[i:]
+	}
+
+	if len(key) == 0 && n.data != nil {
+		return buf, true
+	}
+	if len(key) > 0 {
+		c := key[0]
+		for _, b := range [2]byte{c, swapCaseASCII(c)} {
+			if child := n.children[b]; child != nil {
+				if result, ok := child.fold(key, buf); ok {
+					return result, true
+				}
+			}
+			if b == swapCaseASCII(b) {
+				// not a letter
+				break
+			}
+		}
+	}
+	for _, child := range n.pchildren {
+		if result, ok := child.fold(key, buf); ok {
+			return result, true
+		}
+	}
+	return buf, false
+}
+
+// equalFoldASCII reports whether the strings of the same length are equal regardless of the case of ASCII letters.
+func equalFoldASCII(s, t string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != t[i] && s[i] != swapCaseASCII(t[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// swapCaseASCII returns the other case of an ASCII letter, and any other byte unchanged.
+func swapCaseASCII(c byte) byte {
+	if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' {
+		return c ^ 0x20
+	}
+	return c
+}
+
+// redirectHandlers returns the handlers redirecting the request to the given canonical path, keeping its query string.
+// GET and HEAD requests are redirected with http.StatusMovedPermanently, and the others with
+// http.StatusPermanentRedirect so that their method and body are kept.
+func (r *Router) redirectHandlers(path string) []Handler {
+	return combineHandlers(r.handlers, []Handler{func(c *Context) error {
+		u := *c.Request.URL
+		if r.UseEscapedPath {
+			u.Path, _ = url.PathUnescape(path)
+			u.RawPath = path
+		} else {
+			u.Path, u.RawPath = path, ""
+		}
+		status := http.StatusPermanentRedirect
+		if c.Request.Method == "GET" || c.Request.Method == "HEAD" {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(c.Response, c.Request, u.RequestURI(), status)
+		c.Abort()
+		return nil
+	}})
+}
@@ -0,0 +1,110 @@
+package neo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		path, expected string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"/users/12", "/users/12"},
+		{"/.well-known/x", "/.well-known/x"},
+		{"//users//12", "/users/12"},
+		{"/users/./12", "/users/12"},
+		{"/users/a/../12", "/users/12"},
+		{"/../users", "/users"},
+		{"/users/12/", "/users/12/"},
+		{"/users/12/..", "/users"},
+		{"/users//", "/users/"},
+		{"users", "/users"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, cleanPath(test.path), test.path)
+	}
+}
+
+func TestRouterFoldPath(t *testing.T) {
+	router := New()
+	h := func(c *Context) error { return c.Write(c.Param("id")) }
+	router.Get("/users/<id>", h)
+	router.Get("/Users/list", h)
+	router.Post(`/orders/<id:\d+>/Items`, h)
+	router.Get("/files/<path...>", h)
+	router.Host("<sub>.example.com").Get("/Admin", h)
+
+	tests := []struct {
+		host, path, expected string
+	}{
+		{"", "/USERS/AbC", "/users/AbC"},
+		{"", "/users/list", "/users/list"},
+		{"", "/USERS/LIST", "/Users/list"},
+		{"", "/uSERS/LIST", "/users/LIST"},
+		{"", "/ORDERS/12/items", "/orders/12/Items"},
+		{"", "/ORDERS/ab/items", ""},
+		{"", "/Files/A/B", "/files/A/B"},
+		{"", "/unknown", ""},
+		{"api.example.com", "/admin", "/Admin"},
+		{"", "/admin", ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, router.foldPath(test.host, test.path), test.path)
+	}
+}
+
+func TestRouterCanonicalPath(t *testing.T) {
+	router := New()
+	router.Get("/users/<id>", func(c *Context) error { return c.Write("user " + c.Param("id")) })
+	router.Post("/users", func(c *Context) error { return c.Write("created") })
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/", nil)
+		req.URL.Path, req.URL.RawQuery = path, ""
+		if i := strings.IndexByte(path, '?'); i >= 0 {
+			req.URL.Path, req.URL.RawQuery = path[:i], path[i+1:]
+		}
+		router.ServeHTTP(res, req)
+		return res
+	}
+
+	assert.Equal(t, http.StatusNotFound, serve("GET", "//users/./AbC").Code)
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/USERS/AbC").Code)
+
+	router.CleanPath = true
+	router.CaseInsensitive = true
+	res := serve("GET", "//USERS/x/../AbC")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "user AbC", res.Body.String())
+	assert.Equal(t, "created", serve("POST", "/Users").Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, serve("PUT", "/Users").Code)
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/members/1").Code)
+
+	router.RedirectCanonicalPath = true
+	res = serve("GET", "//USERS/x/../AbC?tab=1")
+	assert.Equal(t, http.StatusMovedPermanently, res.Code)
+	assert.Equal(t, "/users/AbC?tab=1", res.Header().Get("Location"))
+	res = serve("POST", "/members")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	res = serve("POST", "/Users")
+	assert.Equal(t, http.StatusPermanentRedirect, res.Code)
+	assert.Equal(t, "/users", res.Header().Get("Location"))
+	res = serve("GET", "/users/AbC")
+	assert.Equal(t, "user AbC", res.Body.String())
+
+	// a canonical path without a route for the method is not redirected
+	res = serve("PUT", "/USERS/AbC")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS", res.Header().Get("Allow"))
+	assert.Empty(t, res.Header().Get("Location"))
+	res = serve("OPTIONS", "//users/AbC")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Empty(t, res.Header().Get("Location"))
+}
@@ -4,6 +4,8 @@ func (rg *RouteGroup) CatchAll(handlers ...Handler) *RouteGroup {
 	hh := make([]Handler, len(rg.handlers)+len(handlers))
 	copy(hh, rg.handlers)
 	copy(hh[len(rg.handlers):], handlers)
+	rg.router.lock()
 	rg.router.catchAll.Insert(rg.prefix, hh)
+	rg.router.mu.Unlock()
 	return rg
 }
@@ -0,0 +1,332 @@
+package neo
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// compactStore is a read-only version of store optimized for matching. It is built from a store once all routes
+// have been added (see Router.Freeze). Only the nodes with several static children keep a table indexing them
+// by the first bytes of their keys, and parameters with a single character class, such as "<id:\d+>" or
+// "<name:[a-z]+>", are matched without regular expressions. Matching a route does not allocate memory, unless
+// a parameter requires a regular expression. Routes with such parameters are matched several times faster than
+// by a store, while other routes are matched about as fast.
+type compactStore struct {
+	root *cnode
+}
+
+// cnode kinds
+const (
+	cnodeStatic  = iota // a static node
+	cnodeSegment        // a param node matching non-"/" characters
+	cnodeRest           // a param node matching the rest of the key
+	cnodeClass          // a param node matching a single character class
+	cnodeRegex          // a param node matching a regular expression
+)
+
+// cnode is a node of a compactStore.
+type cnode struct {
+	kind int
+	key  string
+	data interface{}
+
+	order    int
+	minOrder int
+
+	children  []*cnode     // the static children, in ascending order of the first bytes of their keys
+	first     byte         // the first byte of the key of the only static child
+	index     *[256]*cnode // the static children by the first bytes of their keys, if there are several
+	pchildren []*cnode     // the param children
+
+	class  *byteClass     // the characters matched by a cnodeClass node
+	regex  *regexp.Regexp // the regular expression of a cnodeRegex node
+	pindex int
+	pnames []string
+}
+
+// byteClass is a set of bytes matched by a param with a single character class.
+type byteClass struct {
+	set      [256]bool
+	allowNil bool // whether the class may match an empty string ("*" quantifier)
+}
+
+// compact builds a compactStore with the same data as the store.
+func (s *store) compact() *compactStore {
+	return &compactStore{root: compactNode(s.root)}
+}
+
+// Add panics because a compactStore cannot be changed.
+func (s *compactStore) Add(key string, data interface{}) int {
+	panic("neo: cannot add a route to a compacted store")
+}
+
+// Get returns the data item matching the given concrete key, like store.Get.
+func (s *compactStore) Get(path string, pvalues []string) (data interface{}, pnames []string) {
+	data, pnames, _ = s.root.get(path, pvalues, 0)
+	return
+}
+
+// GetAfter returns the data item matching the given concrete key that was added first after the data item
+// with the given order, like store.GetAfter.
+func (s *compactStore) GetAfter(path string, pvalues []string, after int) (data interface{}, pnames []string, order int) {
+	data, pnames, order = s.root.get(path, pvalues, after)
+	if data == nil {
+		order = 0
+	}
+	return
+}
+
+// String dumps the tree kept in the store as a string.
+func (s *compactStore) String() string {
+	return s.root.print(0)
+}
+
+// compactNode converts a store node and its descendants into cnodes.
+func compactNode(n *node) *cnode {
+	c := &cnode{
+		kind:     cnodeStatic,
+		key:      n.key,
+		data:     n.data,
+		order:    n.order,
+		minOrder: n.minOrder,
+		pindex:   n.pindex,
+		pnames:   n.pnames,
+	}
+	if !n.static {
+		c.kind = cnodeSegment
+		if n.regex != nil {
+			pattern := strings.TrimPrefix(n.regex.String(), "^")
+			if pattern == ".*" {
+				c.kind = cnodeRest
+			} else if c.class = parseByteClass(pattern); c.class != nil {
+				c.kind = cnodeClass
+			} else {
+				c.kind, c.regex = cnodeRegex, n.regex
+			}
+		}
+	}
+
+	var indices []byte
+	for i, child := range n.children {
+		if child != nil {
+			indices = append(indices, byte(i))
+			c.children = append(c.children, compactNode(child))
+		}
+	}
+	if len(indices) == 1 {
+		c.first = indices[0]
+	} else if len(indices) > 1 {
+		c.index = &[256]*cnode{}
+		for i, b := range indices {
+			c.index[b] = c.children[i]
+		}
+	}
+	for _, child := range n.pchildren {
+		c.pchildren = append(c.pchildren, compactNode(child))
+	}
+
+	// merge a static node without data into its only static child
+	if c.kind == cnodeStatic && c.data == nil && len(c.children) == 1 && len(c.pchildren) == 0 && c.key != "" {
+		child := c.children[0]
+		child.key = c.key + child.key
+		child.minOrder = c.minOrder
+		return child
+	}
+	return c
+}
+
+// parseByteClass returns the byteClass of a pattern consisting of a single character class, such as "\d+",
+// "[a-z0-9]*" or "[^/]+". Nil is returned for any other pattern.
+func parseByteClass(pattern string) *byteClass {
+	if len(pattern) < 2 {
+		return nil
+	}
+	bc := &byteClass{}
+	switch pattern[len(pattern)-1] {
+	case '*':
+		bc.allowNil = true
+	case '+':
+	default:
+		return nil
+	}
+	pattern = pattern[:len(pattern)-1]
+
+	switch {
+	case pattern == `\d`:
+		bc.addRange('0', '9')
+	case pattern == `\w`:
+		bc.addWord()
+	case len(pattern) > 2 && pattern[0] == '[' && pattern[len(pattern)-1] == ']':
+		if !bc.parseSet(pattern[1 : len(pattern)-1]) {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return bc
+}
+
+// parseSet adds the characters listed between the square brackets of a character class.
+// It returns false if the class uses a syntax not supported by byteClass.
+func (bc *byteClass) parseSet(s string) bool {
+	negated := strings.HasPrefix(s, "^")
+	if negated {
+		s = s[1:]
+	}
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 0x80 || c == '[' || c == ']':
+			return false
+		case c == '\\':
+			if i++; i == len(s) {
+				return false
+			}
+			switch e := s[i]; {
+			case e == 'd':
+				bc.addRange('0', '9')
+			case e == 'w':
+				bc.addWord()
+			case strings.IndexByte(`\-./]^[`, e) >= 0:
+				bc.set[e] = true
+			default:
+				return false
+			}
+		case i+2 < len(s) && s[i+1] == '-':
+			if s[i+2] == '\\' || s[i+2] >= 0x80 || s[i+2] < c {
+				return false
+			}
+			bc.addRange(c, s[i+2])
+			i += 2
+		default:
+			bc.set[c] = true
+		}
+	}
+	if negated {
+		for i := range bc.set {
+			// a negated class also matches non-ASCII characters, byte by byte
+			bc.set[i] = !bc.set[i]
+		}
+	}
+	return true
+}
+
+func (bc *byteClass) addRange(from, to byte) {
+	for c := int(from); c <= int(to); c++ {
+		bc.set[c] = true
+	}
+}
+
+func (bc *byteClass) addWord() {
+	bc.addRange('0', '9')
+	bc.addRange('a', 'z')
+	bc.addRange('A', 'Z')
+	bc.set['_'] = true
+}
+
+// child returns the static child whose key starts with the given byte.
+func (n *cnode) child(c byte) *cnode {
+	if n.index != nil {
+		return n.index[c]
+	}
+	if len(n.children) == 1 && n.first == c {
+		return n.children[0]
+	}
+	return nil
+}
+
+// get returns the data item with the key matching the tree rooted at the current node.
+// Like node.get, it prefers the data item added first after the given order when multiple items match the key.
+func (n *cnode) get(key string, pvalues []string, after int) (data interface{}, pnames []string, order int) {
+	order = math.MaxInt32
+
+repeat:
+	switch n.kind {
+	case cnodeStatic:
+		// compare backwards, as keys sharing a prefix tend to differ at the end
+		nkl := len(n.key)
+		if nkl > len(key) {
+			return
+		}
+		for i := nkl - 1; i >= 0; i-- {
+			if n.key[i] != key[i] {
+				return
+			}
+		}
+		key = key[nkl:]
+	case cnodeSegment:
+		i := strings.IndexByte(key, '/')
+		if i < 0 {
+			i = len(key)
+		}
+		pvalues[n.pindex], key = key[:i], key[i:]
+	case cnodeRest:
+		pvalues[n.pindex], key = key, ""
+	case cnodeClass:
+		i := 0
+		for i < len(key) && n.class.set[key[i]] {
+			i++
+		}
+		if i == 0 && !n.class.allowNil {
+			return
+		}
+		pvalues[n.pindex], key = key[:i], key[i:]
+	default:
+		match := n.regex.FindStringIndex(key)
+		if match == nil {
+			return
+		}
+		pvalues[n.pindex], key = key[:match[1]], key[match[1]:]
+	}
+
+	var winner *cnode // the child providing the data, if any
+	if len(key) > 0 {
+		if child := n.child(key[0]); child != nil {
+			if len(n.pchildren) == 0 {
+				// avoid recursion when there are no param children
+				n = child
+				goto repeat
+			}
+			if data, pnames, order = child.get(key, pvalues, after); data != nil {
+				winner = child
+			}
+		}
+	} else if n.data != nil && n.order > after {
+		// do not return yet: a param node may match an empty string with smaller order
+		data, pnames, order = n.data, n.pnames, n.order
+	}
+
+	// try matching param children. The parameter values of a failed attempt may overwrite the ones
+	// of the data found before, in which case the winner is matched again to restore them.
+	dirty := false
+	for _, child := range n.pchildren {
+		if child.minOrder >= order {
+			continue
+		}
+		if d, p, o := child.get(key, pvalues, after); d != nil && o < order {
+			data, pnames, order, winner, dirty = d, p, o, child, false
+		} else if data != nil {
+			dirty = true
+		}
+	}
+	if dirty && winner != nil {
+		winner.get(key, pvalues, after)
+	}
+	return
+}
+
+func (n *cnode) print(level int) string {
+	r := fmt.Sprintf("%v{key: %v, kind: %v, regex: %v, data: %v, order: %v, minOrder: %v, pindex: %v, pnames: %v}\n", strings.Repeat(" ", level<<2), n.key, n.kind, n.regex, n.data, n.order, n.minOrder, n.pindex, n.pnames)
+	for _, child := range n.children {
+		r += child.print(level + 1)
+	}
+	for _, child := range n.pchildren {
+		r += child.print(level + 1)
+	}
+	return r
+}
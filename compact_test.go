@@ -0,0 +1,434 @@
+package neo
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactStoreGet(t *testing.T) {
+	keys := []string{
+		"/gopher/bumper.png",
+		"/gopher/bumper192x108.png",
+		"/gopher/doc.png",
+		"/gopher/bumper320x180.png",
+		"/gopher/docpage.png",
+		"/gopher/doc",
+		"/users/<id>",
+		"/users/<id>/profile",
+		"/users/<id>/<accnt:\\d+>/address",
+		"/users/<id>/age",
+		"/users/<id>/<accnt:\\d+>",
+		"/users/<id>/test/<name>",
+		"/users/abc/<id>/<name>",
+		"",
+		"/all/<:.*>",
+		"/posts/<slug:[a-z][a-z0-9-]*>",
+		"/posts/<id:[0-9]+>/comments",
+		"/posts/<id:[0-9]+>/<page:[^/]*>",
+		"/tags/<tag:\\w+>.<format:[a-z]+>",
+	}
+	s := newStore()
+	for i, key := range keys {
+		s.Add(key, i)
+	}
+	c := s.compact()
+
+	paths := []string{
+		"/gopher/bumper.png", "/gopher/bumper192x108.png", "/gopher/doc.png", "/gopher/docpage.png", "/gopher/doc",
+		"/gopher/", "/g", "", "/users/abc", "/users/abc/profile", "/users/abc/123/address", "/users/abcd/age",
+		"/users/abc/123", "/users/abc/test/123", "/users/abc/xyz/123", "/users/abc/xyz", "/users/abc/test",
+		"/all", "/all/", "/all/a/b", "/posts/hello-world", "/posts/12/comments", "/posts/12/", "/posts/12/x",
+		"/posts/12", "/posts/-a", "/tags/go_lang.json", "/tags/go.", "/tags/.json",
+	}
+	expected, actual := make([]string, 2), make([]string, 2)
+	for _, path := range paths {
+		d1, p1 := s.Get(path, expected)
+		d2, p2 := c.Get(path, actual)
+		assert.Equal(t, d1, d2, path)
+		assert.Equal(t, p1, p2, path)
+		assert.Equal(t, expected[:len(p1)], actual[:len(p2)], path)
+	}
+	assert.Panics(t, func() { c.Add("/x", 1) })
+}
+
+func TestParseByteClass(t *testing.T) {
+	tests := []struct {
+		pattern, matched, unmatched string
+		allowNil                    bool
+	}{
+		{`\d+`, "0123456789", "a/-", false},
+		{`\w*`, "azAZ09_", "-/.", true},
+		{`[a-z0-9-]+`, "az09-", "A_/", false},
+		{`[^/]+`, "a-Z.\xe4", "/", false},
+		{`[\d.]+`, "09.", "a/", false},
+	}
+	for _, test := range tests {
+		bc := parseByteClass(test.pattern)
+		if !assert.NotNil(t, bc, test.pattern) {
+			continue
+		}
+		assert.Equal(t, test.allowNil, bc.allowNil, test.pattern)
+		for i := 0; i < len(test.matched); i++ {
+			assert.True(t, bc.set[test.matched[i]], test.pattern+" "+test.matched[i:i+1])
+		}
+		for i := 0; i < len(test.unmatched); i++ {
+			assert.False(t, bc.set[test.unmatched[i]], test.pattern+" "+test.unmatched[i:i+1])
+		}
+	}
+	for _, pattern := range []string{`\d`, `-?[0-9]+`, `[0-9]{4}`, `[[:alpha:]]+`, `[é]+`, `(?i)[a-z]+`, `.*`, `a+`} {
+		assert.Nil(t, parseByteClass(pattern), pattern)
+	}
+}
+
+type nullResponseWriter struct {
+	header http.Header
+}
+
+func (w *nullResponseWriter) Header() http.Header         { return w.header }
+func (w *nullResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *nullResponseWriter) WriteHeader(int)             {}
+
+func TestRouterServeHTTPAllocs(t *testing.T) {
+	router := New()
+	router.Get("/users", func(*Context) error { return nil })
+	router.Get("/users/<id>", func(*Context) error { return nil })
+	router.Get("/users/<id:\\d+>/posts/<post:[a-z-]+>", func(*Context) error { return nil })
+	router.Get("/static/<:.*>", func(*Context) error { return nil })
+	router.Freeze()
+
+	res := &nullResponseWriter{header: http.Header{}}
+	for _, path := range []string{"/users", "/users/abc", "/users/12/posts/hello-world", "/static/js/app.js"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		allocs := testing.AllocsPerRun(100, func() {
+			router.ServeHTTP(res, req)
+		})
+		assert.Zero(t, allocs, path)
+	}
+}
+
+// parseAPI is the route set of the Parse REST API.
+var parseAPI = []string{
+	"POST /1/classes/:className",
+	"GET /1/classes/:className/:objectId",
+	"PUT /1/classes/:className/:objectId",
+	"GET /1/classes/:className",
+	"DELETE /1/classes/:className/:objectId",
+	"POST /1/users",
+	"GET /1/login",
+	"GET /1/users/:objectId",
+	"PUT /1/users/:objectId",
+	"GET /1/users",
+	"DELETE /1/users/:objectId",
+	"POST /1/requestPasswordReset",
+	"POST /1/roles",
+	"GET /1/roles/:objectId",
+	"PUT /1/roles/:objectId",
+	"GET /1/roles",
+	"DELETE /1/roles/:objectId",
+	"POST /1/files/:fileName",
+	"POST /1/events/:eventName",
+	"POST /1/push",
+	"POST /1/installations",
+	"GET /1/installations/:objectId",
+	"PUT /1/installations/:objectId",
+	"GET /1/installations",
+	"DELETE /1/installations/:objectId",
+	"POST /1/functions",
+}
+
+// githubAPI is a route set modeled after the GitHub REST API.
+var githubAPI = []string{
+	"GET /authorizations",
+	"GET /authorizations/:id",
+	"POST /authorizations",
+	"DELETE /authorizations/:id",
+	"GET /applications/:client_id/tokens/:access_token",
+	"DELETE /applications/:client_id/tokens",
+	"DELETE /applications/:client_id/tokens/:access_token",
+	"GET /events",
+	"GET /repos/:owner/:repo/events",
+	"GET /networks/:owner/:repo/events",
+	"GET /orgs/:org/events",
+	"GET /users/:user/received_events",
+	"GET /users/:user/received_events/public",
+	"GET /users/:user/events",
+	"GET /users/:user/events/public",
+	"GET /users/:user/events/orgs/:org",
+	"GET /feeds",
+	"GET /notifications",
+	"GET /repos/:owner/:repo/notifications",
+	"PUT /notifications",
+	"PUT /repos/:owner/:repo/notifications",
+	"GET /notifications/threads/:id",
+	"GET /notifications/threads/:id/subscription",
+	"PUT /notifications/threads/:id/subscription",
+	"DELETE /notifications/threads/:id/subscription",
+	"GET /repos/:owner/:repo/stargazers",
+	"GET /users/:user/starred",
+	"GET /user/starred",
+	"GET /user/starred/:owner/:repo",
+	"PUT /user/starred/:owner/:repo",
+	"DELETE /user/starred/:owner/:repo",
+	"GET /repos/:owner/:repo/subscribers",
+	"GET /users/:user/subscriptions",
+	"GET /user/subscriptions",
+	"GET /repos/:owner/:repo/subscription",
+	"PUT /repos/:owner/:repo/subscription",
+	"DELETE /repos/:owner/:repo/subscription",
+	"GET /user/subscriptions/:owner/:repo",
+	"PUT /user/subscriptions/:owner/:repo",
+	"DELETE /user/subscriptions/:owner/:repo",
+	"GET /users/:user/gists",
+	"GET /gists",
+	"GET /gists/:id",
+	"POST /gists",
+	"PUT /gists/:id/star",
+	"DELETE /gists/:id/star",
+	"GET /gists/:id/star",
+	"POST /gists/:id/forks",
+	"DELETE /gists/:id",
+	"GET /repos/:owner/:repo/git/blobs/:sha",
+	"POST /repos/:owner/:repo/git/blobs",
+	"GET /repos/:owner/:repo/git/commits/:sha",
+	"POST /repos/:owner/:repo/git/commits",
+	"GET /repos/:owner/:repo/git/refs",
+	"POST /repos/:owner/:repo/git/refs",
+	"GET /repos/:owner/:repo/git/tags/:sha",
+	"POST /repos/:owner/:repo/git/tags",
+	"GET /repos/:owner/:repo/git/trees/:sha",
+	"POST /repos/:owner/:repo/git/trees",
+	"GET /issues",
+	"GET /user/issues",
+	"GET /orgs/:org/issues",
+	"GET /repos/:owner/:repo/issues",
+	"GET /repos/:owner/:repo/issues/:number",
+	"POST /repos/:owner/:repo/issues",
+	"GET /repos/:owner/:repo/assignees",
+	"GET /repos/:owner/:repo/assignees/:assignee",
+	"GET /repos/:owner/:repo/issues/:number/comments",
+	"POST /repos/:owner/:repo/issues/:number/comments",
+	"GET /repos/:owner/:repo/issues/:number/events",
+	"GET /repos/:owner/:repo/labels",
+	"GET /repos/:owner/:repo/labels/:name",
+	"POST /repos/:owner/:repo/labels",
+	"DELETE /repos/:owner/:repo/labels/:name",
+	"GET /repos/:owner/:repo/issues/:number/labels",
+	"POST /repos/:owner/:repo/issues/:number/labels",
+	"DELETE /repos/:owner/:repo/issues/:number/labels/:name",
+	"PUT /repos/:owner/:repo/issues/:number/labels",
+	"DELETE /repos/:owner/:repo/issues/:number/labels",
+	"GET /repos/:owner/:repo/milestones/:number/labels",
+	"GET /repos/:owner/:repo/milestones",
+	"GET /repos/:owner/:repo/milestones/:number",
+	"POST /repos/:owner/:repo/milestones",
+	"DELETE /repos/:owner/:repo/milestones/:number",
+	"GET /emojis",
+	"GET /gitignore/templates",
+	"GET /gitignore/templates/:name",
+	"POST /markdown",
+	"POST /markdown/raw",
+	"GET /meta",
+	"GET /rate_limit",
+	"GET /users/:user/orgs",
+	"GET /user/orgs",
+	"GET /orgs/:org",
+	"GET /orgs/:org/members",
+	"GET /orgs/:org/members/:user",
+	"DELETE /orgs/:org/members/:user",
+	"GET /orgs/:org/public_members",
+	"GET /orgs/:org/public_members/:user",
+	"PUT /orgs/:org/public_members/:user",
+	"DELETE /orgs/:org/public_members/:user",
+	"GET /orgs/:org/teams",
+	"GET /teams/:id",
+	"POST /orgs/:org/teams",
+	"DELETE /teams/:id",
+	"GET /teams/:id/members",
+	"GET /teams/:id/members/:user",
+	"PUT /teams/:id/members/:user",
+	"DELETE /teams/:id/members/:user",
+	"GET /teams/:id/repos",
+	"GET /teams/:id/repos/:owner/:repo",
+	"PUT /teams/:id/repos/:owner/:repo",
+	"DELETE /teams/:id/repos/:owner/:repo",
+	"GET /user/teams",
+	"GET /repos/:owner/:repo/pulls",
+	"GET /repos/:owner/:repo/pulls/:number",
+	"POST /repos/:owner/:repo/pulls",
+	"GET /repos/:owner/:repo/pulls/:number/commits",
+	"GET /repos/:owner/:repo/pulls/:number/files",
+	"GET /repos/:owner/:repo/pulls/:number/merge",
+	"PUT /repos/:owner/:repo/pulls/:number/merge",
+	"GET /repos/:owner/:repo/pulls/:number/comments",
+	"PUT /repos/:owner/:repo/pulls/:number/comments",
+	"GET /user/repos",
+	"GET /users/:user/repos",
+	"GET /orgs/:org/repos",
+	"GET /repositories",
+	"POST /user/repos",
+	"POST /orgs/:org/repos",
+	"GET /repos/:owner/:repo",
+	"DELETE /repos/:owner/:repo",
+	"GET /repos/:owner/:repo/contributors",
+	"GET /repos/:owner/:repo/languages",
+	"GET /repos/:owner/:repo/teams",
+	"GET /repos/:owner/:repo/tags",
+	"GET /repos/:owner/:repo/branches",
+	"GET /repos/:owner/:repo/branches/:branch",
+	"GET /repos/:owner/:repo/collaborators",
+	"GET /repos/:owner/:repo/collaborators/:user",
+	"PUT /repos/:owner/:repo/collaborators/:user",
+	"DELETE /repos/:owner/:repo/collaborators/:user",
+	"GET /repos/:owner/:repo/comments",
+	"GET /repos/:owner/:repo/commits/:sha/comments",
+	"POST /repos/:owner/:repo/commits/:sha/comments",
+	"GET /repos/:owner/:repo/comments/:id",
+	"DELETE /repos/:owner/:repo/comments/:id",
+	"GET /repos/:owner/:repo/commits",
+	"GET /repos/:owner/:repo/commits/:sha",
+	"GET /repos/:owner/:repo/readme",
+	"GET /repos/:owner/:repo/keys",
+	"GET /repos/:owner/:repo/keys/:id",
+	"POST /repos/:owner/:repo/keys",
+	"DELETE /repos/:owner/:repo/keys/:id",
+	"GET /repos/:owner/:repo/downloads",
+	"GET /repos/:owner/:repo/downloads/:id",
+	"DELETE /repos/:owner/:repo/downloads/:id",
+	"GET /repos/:owner/:repo/forks",
+	"POST /repos/:owner/:repo/forks",
+	"GET /repos/:owner/:repo/hooks",
+	"GET /repos/:owner/:repo/hooks/:id",
+	"POST /repos/:owner/:repo/hooks",
+	"POST /repos/:owner/:repo/hooks/:id/tests",
+	"DELETE /repos/:owner/:repo/hooks/:id",
+	"POST /repos/:owner/:repo/merges",
+	"GET /repos/:owner/:repo/releases",
+	"GET /repos/:owner/:repo/releases/:id",
+	"POST /repos/:owner/:repo/releases",
+	"DELETE /repos/:owner/:repo/releases/:id",
+	"GET /repos/:owner/:repo/releases/:id/assets",
+	"GET /repos/:owner/:repo/stats/contributors",
+	"GET /repos/:owner/:repo/stats/commit_activity",
+	"GET /repos/:owner/:repo/stats/code_frequency",
+	"GET /repos/:owner/:repo/stats/participation",
+	"GET /repos/:owner/:repo/stats/punch_card",
+	"GET /repos/:owner/:repo/statuses/:ref",
+	"POST /repos/:owner/:repo/statuses/:ref",
+	"GET /search/repositories",
+	"GET /search/code",
+	"GET /search/issues",
+	"GET /search/users",
+	"GET /legacy/issues/search/:owner/:repository/:state/:keyword",
+	"GET /legacy/repos/search/:keyword",
+	"GET /legacy/user/search/:keyword",
+	"GET /legacy/user/email/:email",
+	"GET /users/:user",
+	"GET /user",
+	"GET /users",
+	"GET /user/emails",
+	"POST /user/emails",
+	"DELETE /user/emails",
+	"GET /users/:user/followers",
+	"GET /user/followers",
+	"GET /users/:user/following",
+	"GET /user/following",
+	"GET /user/following/:user",
+	"GET /users/:user/following/:target_user",
+	"PUT /user/following/:user",
+	"DELETE /user/following/:user",
+	"GET /users/:user/keys",
+	"GET /user/keys",
+	"GET /user/keys/:id",
+	"POST /user/keys",
+	"DELETE /user/keys/:id",
+}
+
+// paramPlaceholder matches a ":param" placeholder in a benchmark route.
+var paramPlaceholder = regexp.MustCompile(`:(\w+)`)
+
+// benchRoute is a route of a benchmark route set, with its route path and a matching request path.
+type benchRoute struct {
+	method, path, request string
+}
+
+// parseBenchRoutes converts routes in the form of "METHOD /path/:param" into neo route paths and request paths.
+// The token replaces the ":param" placeholders in the route paths, with "$1" standing for the parameter name.
+func parseBenchRoutes(routes []string, token string) []benchRoute {
+	var result []benchRoute
+	for _, route := range routes {
+		var br benchRoute
+		fmt.Sscan(route, &br.method, &br.path)
+		br.request = paramPlaceholder.ReplaceAllString(br.path, "$1")
+		br.path = paramPlaceholder.ReplaceAllString(br.path, token)
+		result = append(result, br)
+	}
+	return result
+}
+
+func benchmarkStore(b *testing.B, routes []string, token string, compact bool) {
+	stores := map[string]routeStore{}
+	maxParams := 0
+	brs := parseBenchRoutes(routes, token)
+	for _, br := range brs {
+		s := stores[br.method]
+		if s == nil {
+			s = newStore()
+			stores[br.method] = s
+		}
+		if n := s.Add(br.path, br.path); n > maxParams {
+			maxParams = n
+		}
+	}
+	if compact {
+		compactStores(stores)
+	}
+	pvalues := make([]string, maxParams)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, br := range brs {
+			if data, _ := stores[br.method].Get(br.request, pvalues); data == nil {
+				b.Fatalf("no match for %v %v", br.method, br.request)
+			}
+		}
+	}
+}
+
+func benchmarkRouter(b *testing.B, routes []string, freeze bool) {
+	router := New()
+	brs := parseBenchRoutes(routes, "<$1>")
+	for _, br := range brs {
+		router.To(br.method, br.path, func(*Context) error { return nil })
+	}
+	if freeze {
+		router.Freeze()
+	}
+	var reqs []*http.Request
+	for _, br := range brs {
+		req, _ := http.NewRequest(br.method, br.request, nil)
+		reqs = append(reqs, req)
+	}
+	res := &nullResponseWriter{header: http.Header{}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, req := range reqs {
+			router.ServeHTTP(res, req)
+		}
+	}
+}
+
+func BenchmarkStoreParseAll(b *testing.B)           { benchmarkStore(b, parseAPI, "<$1>", false) }
+func BenchmarkCompactStoreParseAll(b *testing.B)    { benchmarkStore(b, parseAPI, "<$1>", true) }
+func BenchmarkStoreGitHubAll(b *testing.B)          { benchmarkStore(b, githubAPI, "<$1>", false) }
+func BenchmarkCompactStoreGitHubAll(b *testing.B)   { benchmarkStore(b, githubAPI, "<$1>", true) }
+func BenchmarkStoreGitHubRegex(b *testing.B)        { benchmarkStore(b, githubAPI, "<$1:[^/]+>", false) }
+func BenchmarkCompactStoreGitHubRegex(b *testing.B) { benchmarkStore(b, githubAPI, "<$1:[^/]+>", true) }
+func BenchmarkRouterParseAll(b *testing.B)          { benchmarkRouter(b, parseAPI, false) }
+func BenchmarkFrozenRouterParseAll(b *testing.B)    { benchmarkRouter(b, parseAPI, true) }
+func BenchmarkRouterGitHubAll(b *testing.B)         { benchmarkRouter(b, githubAPI, false) }
+func BenchmarkFrozenRouterGitHubAll(b *testing.B)   { benchmarkRouter(b, githubAPI, true) }
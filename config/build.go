@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/caeret/neo"
+	"github.com/caeret/neo/file"
+)
+
+// Build creates a new router from the configuration, resolving the handlers and middleware by their names
+// in the registry. All problems found in the configuration are reported together as Errors, sorted by line.
+func (g *Group) Build(registry *Registry) (*neo.Router, error) {
+	b := &builder{registry: registry, router: neo.New()}
+	b.root(g)
+	if len(b.errs) > 0 {
+		sort.SliceStable(b.errs, func(i, j int) bool {
+			return b.errs[i].Line < b.errs[j].Line
+		})
+		return nil, b.errs
+	}
+	return b.router, nil
+}
+
+// builder adds the routes described by a configuration to a router.
+type builder struct {
+	registry *Registry
+	router   *neo.Router
+	errs     Errors
+}
+
+func (b *builder) errorf(line int, format string, args ...interface{}) {
+	b.errs = append(b.errs, &Error{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// root adds the routes of the root group. The middleware of the root group is applied to the whole router,
+// including the NotFound handlers.
+func (b *builder) root(g *Group) {
+	b.errs = append(b.errs, g.errs...)
+	b.use(b.router.UseNamed, g.Middleware)
+	rg := &b.router.RouteGroup
+	if g.Host != "" {
+		rg = b.host(g.line, g.Host, rg)
+	}
+	b.members(g, rg.Group(g.Prefix).Skip(g.Skip...), g.Prefix, g.Host == "")
+}
+
+// group adds the routes of a nested group to the parent route group.
+func (b *builder) group(g *Group, parent *neo.RouteGroup, parentPrefix string, topLevel bool) {
+	b.errs = append(b.errs, g.errs...)
+	prefix := parentPrefix + g.Prefix
+	var rg *neo.RouteGroup
+	switch {
+	case g.Host == "":
+		rg = parent.Group(g.Prefix)
+	case topLevel:
+		rg = b.host(g.line, g.Host, parent).Group(prefix)
+	default:
+		b.errorf(g.line, "host is only allowed for the top-level groups")
+		rg = parent.Group(g.Prefix)
+	}
+	rg.Skip(g.Skip...)
+	b.use(rg.UseNamed, g.Middleware)
+	b.members(g, rg, prefix, false)
+}
+
+// members adds the routes, static file mounts and nested groups of a group.
+func (b *builder) members(g *Group, rg *neo.RouteGroup, prefix string, topLevel bool) {
+	for i := range g.Routes {
+		b.route(&g.Routes[i], rg)
+	}
+	for i := range g.Static {
+		b.static(&g.Static[i], rg, prefix)
+	}
+	for i := range g.Groups {
+		b.group(&g.Groups[i], rg, prefix, topLevel)
+	}
+}
+
+func (b *builder) route(r *Route, rg *neo.RouteGroup) {
+	b.errs = append(b.errs, r.errs...)
+	valid := b.checkPath(r.line, r.Path)
+	if r.Method == "" {
+		b.errorf(r.line, "method is required")
+		valid = false
+	}
+	for _, method := range strings.Split(r.Method, ",") {
+		if r.Method != "" && !isMethod(method) {
+			b.errorf(r.line, "unknown method %q", method)
+			valid = false
+		}
+	}
+	var handlers []neo.Handler
+	for _, m := range r.Middleware {
+		if h, ok := b.middleware(m); ok {
+			handlers = append(handlers, h)
+		}
+	}
+	if r.Handler == "" {
+		b.errorf(r.line, "handler is required")
+		valid = false
+	} else if h, ok := b.registry.handlers[r.Handler]; !ok {
+		b.errorf(r.line, "unknown handler %q", r.Handler)
+		valid = false
+	} else {
+		handlers = append(handlers, h)
+	}
+	if !valid {
+		return
+	}
+	b.register(r.line, func() {
+		route := rg.To(r.Method, r.Path, handlers...).Skip(r.Skip...)
+		if r.Name != "" {
+			route.Name(r.Name)
+		}
+	})
+}
+
+func (b *builder) static(s *Static, rg *neo.RouteGroup, prefix string) {
+	b.errs = append(b.errs, s.errs...)
+	valid := b.checkPath(s.line, s.Path)
+	if s.Dir == "" {
+		b.errorf(s.line, "dir is required")
+		valid = false
+	}
+	if !valid {
+		return
+	}
+	path := strings.TrimRight(s.Path, "/")
+	b.register(s.line, func() {
+		rg.To("GET,HEAD", path+"/*", file.Server(file.PathMap{prefix + path: "/"}, file.ServerOptions{
+			RootPath:     s.Dir,
+			IndexFile:    s.Index,
+			CatchAllFile: s.CatchAll,
+		}))
+	})
+}
+
+// host returns the route group of the routes matching the host pattern. If the pattern is invalid,
+// the problem is reported and the fallback group is returned so that the group members are still checked.
+func (b *builder) host(line int, pattern string, fallback *neo.RouteGroup) *neo.RouteGroup {
+	rg := fallback
+	if b.checkPattern(line, pattern) {
+		b.register(line, func() {
+			rg = b.router.Host(pattern)
+		})
+	}
+	return rg
+}
+
+// register calls fn, which adds routes to the router, and reports the panic it raises, such as a conflict
+// between routes of a strict router, as a problem at the given line. It returns whether fn succeeded.
+func (b *builder) register(line int, fn func()) (ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			b.errorf(line, "%v", err)
+			ok = false
+		}
+	}()
+	fn()
+	return true
+}
+
+// use registers the middleware to a group under their names.
+func (b *builder) use(useNamed func(string, neo.Handler), mm []Middleware) {
+	for _, m := range mm {
+		if h, ok := b.middleware(m); ok {
+			useNamed(m.Name, h)
+		}
+	}
+}
+
+// middleware creates a middleware handler using the factory in the registry.
+func (b *builder) middleware(m Middleware) (neo.Handler, bool) {
+	b.errs = append(b.errs, m.errs...)
+	factory, ok := b.registry.middleware[m.Name]
+	if !ok {
+		b.errorf(m.line, "unknown middleware %q", m.Name)
+		return nil, false
+	}
+	h, err := factory(m.Options)
+	if err != nil {
+		b.errorf(m.line, "middleware %q: %v", m.Name, err)
+		return nil, false
+	}
+	return h, true
+}
+
+func (b *builder) checkPath(line int, path string) bool {
+	if !strings.HasPrefix(path, "/") {
+		b.errorf(line, "path must start with a slash")
+		return false
+	}
+	return b.checkPattern(line, path)
+}
+
+// paramToken matches the parameter tokens of a route path or a host pattern, like in the neo package.
+var paramToken = regexp.MustCompile(`<([^:>]*)(?::([^>]*))?>`)
+
+// checkPattern reports the parameters of a route path or a host pattern whose regular expressions are invalid.
+func (b *builder) checkPattern(line int, pattern string) bool {
+	valid := true
+	for _, m := range paramToken.FindAllStringSubmatch(pattern, -1) {
+		if _, err := regexp.Compile(m[2]); err != nil {
+			b.errorf(line, "invalid pattern of parameter %q: %v", m[1], err)
+			valid = false
+		}
+	}
+	return valid
+}
+
+func isMethod(method string) bool {
+	for _, m := range neo.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+// Package config builds routers from declarative route configurations written in YAML or JSON.
+//
+// A configuration describes the middleware, routes, static file mounts and nested groups of a router:
+//
+//	middleware:
+//	  - logger
+//	  - name: cors
+//	    options:
+//	      allowOrigins: "*"
+//	routes:
+//	  - method: GET
+//	    path: /health
+//	    handler: health
+//	groups:
+//	  - prefix: /api
+//	    middleware:
+//	      - name: jwt
+//	        options:
+//	          keyEnv: JWT_KEY
+//	    routes:
+//	      - method: GET,HEAD
+//	        path: /users/<id:\d+>
+//	        handler: getUser
+//	        name: user
+//	      - method: POST
+//	        path: /login
+//	        handler: login
+//	        skip: [jwt]
+//	static:
+//	  - path: /assets
+//	    dir: ./public
+//	    index: index.html
+//
+// Handlers and middleware are referenced by the names they are registered with in a Registry.
+// Middleware is added to the groups under its name (see neo.RouteGroup.UseNamed), so that routes
+// and nested groups can skip inherited middleware by listing the names in "skip".
+// Since JSON is a subset of YAML, the same parser is used for both formats.
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/caeret/neo"
+	"gopkg.in/yaml.v3"
+)
+
+// Group describes a group of routes sharing a path prefix and middleware.
+// The root of a configuration is a Group as well.
+type Group struct {
+	// Prefix is the path prefix of the group, appended to the prefix of the parent group.
+	Prefix string `yaml:"prefix" json:"prefix"`
+	// Host is the host pattern that the group routes must match (see neo.Router.Host).
+	// It is only allowed for the root and the top-level groups.
+	Host string `yaml:"host" json:"host"`
+	// Middleware lists the middleware applied to all routes in the group, in order.
+	Middleware []Middleware `yaml:"middleware" json:"middleware"`
+	// Skip lists the names of the middleware inherited from the parent groups that the group does not apply.
+	Skip []string `yaml:"skip" json:"skip"`
+	// Routes lists the routes of the group.
+	Routes []Route `yaml:"routes" json:"routes"`
+	// Static lists the static file mounts of the group.
+	Static []Static `yaml:"static" json:"static"`
+	// Groups lists the nested groups.
+	Groups []Group `yaml:"groups" json:"groups"`
+
+	line int
+	errs Errors // the problems found when decoding the group
+}
+
+// Route describes a route.
+type Route struct {
+	// Method is the HTTP method of the route. Multiple methods are separated by commas.
+	Method string `yaml:"method" json:"method"`
+	// Path is the route path, appended to the prefix of the group.
+	Path string `yaml:"path" json:"path"`
+	// Handler is the name of the handler in the registry.
+	Handler string `yaml:"handler" json:"handler"`
+	// Name is the optional route name (see neo.Route.Name).
+	Name string `yaml:"name" json:"name"`
+	// Middleware lists the middleware applied to the route before the handler, in order.
+	Middleware []Middleware `yaml:"middleware" json:"middleware"`
+	// Skip lists the names of the middleware inherited from the groups that the route does not apply.
+	Skip []string `yaml:"skip" json:"skip"`
+
+	line int
+	errs Errors
+}
+
+// Static describes a directory whose files are served under a URL path via file.Server.
+type Static struct {
+	// Path is the URL path prefix, appended to the prefix of the group.
+	Path string `yaml:"path" json:"path"`
+	// Dir is the directory containing the files, as an absolute path or a path relative to the working directory.
+	Dir string `yaml:"dir" json:"dir"`
+	// Index is the file served for a directory, such as "index.html".
+	Index string `yaml:"index" json:"index"`
+	// CatchAll is the file, relative to Dir, served when no file matches the request.
+	CatchAll string `yaml:"catchAll" json:"catchAll"`
+
+	line int
+	errs Errors
+}
+
+// Middleware references a middleware factory in the registry together with its options.
+// In a configuration, a middleware without options may be written as its name only.
+type Middleware struct {
+	// Name is the name of the middleware factory in the registry.
+	Name string `yaml:"name" json:"name"`
+	// Options are passed to the middleware factory.
+	Options Options `yaml:"options" json:"options"`
+
+	line int
+	errs Errors
+}
+
+// Options holds the options of a middleware as they appear in the configuration.
+type Options struct {
+	node *yaml.Node
+}
+
+// Decode decodes the options into the given value, which is usually a pointer to a struct with yaml tags.
+// Nothing is decoded if no options are given in the configuration.
+func (o Options) Decode(v interface{}) error {
+	if o.node == nil {
+		return nil
+	}
+	return o.node.Decode(v)
+}
+
+// Error describes a problem found in a configuration.
+type Error struct {
+	// File is the name of the configuration file. Empty if the configuration is not loaded from a file.
+	File string
+	// Line is the line in the configuration where the problem is found.
+	Line int
+	// Message describes the problem.
+	Message string
+}
+
+// Error returns the error message prefixed with the location of the problem.
+func (e *Error) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%v:%v: %v", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %v: %v", e.Line, e.Message)
+}
+
+// Errors is the list of problems found in a configuration.
+type Errors []*Error
+
+// Error returns the messages of all problems, one per line.
+func (es Errors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Parse parses a configuration in YAML or JSON.
+// Unknown fields are not reported until the configuration is built.
+func Parse(data []byte) (*Group, error) {
+	var g Group
+	if err := yaml.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Load reads a configuration file and builds a new router from it using the given registry.
+// The problems found in the configuration are reported as Errors pointing at their lines in the file.
+func Load(filename string, registry *Registry) (*neo.Router, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	g, err := Parse(data)
+	if err == nil {
+		var router *neo.Router
+		if router, err = g.Build(registry); err == nil {
+			return router, nil
+		}
+	}
+	if es, ok := err.(Errors); ok {
+		for _, e := range es {
+			e.File = filename
+		}
+		return nil, es
+	}
+	return nil, fmt.Errorf("%v: %w", filename, err)
+}
+
+// UnmarshalYAML decodes a group and records its line.
+func (g *Group) UnmarshalYAML(node *yaml.Node) error {
+	type plain Group
+	g.line = node.Line
+	return decodeMapping(node, (*plain)(g), &g.errs)
+}
+
+// UnmarshalYAML decodes a route and records its line.
+func (r *Route) UnmarshalYAML(node *yaml.Node) error {
+	type plain Route
+	r.line = node.Line
+	return decodeMapping(node, (*plain)(r), &r.errs)
+}
+
+// UnmarshalYAML decodes a static file mount and records its line.
+func (s *Static) UnmarshalYAML(node *yaml.Node) error {
+	type plain Static
+	s.line = node.Line
+	return decodeMapping(node, (*plain)(s), &s.errs)
+}
+
+// UnmarshalYAML decodes a middleware given either as a name or as a mapping, and records its line.
+func (m *Middleware) UnmarshalYAML(node *yaml.Node) error {
+	m.line = node.Line
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&m.Name)
+	}
+	type plain Middleware
+	return decodeMapping(node, (*plain)(m), &m.errs)
+}
+
+// UnmarshalYAML keeps the options node for decoding by the middleware factory.
+func (o *Options) UnmarshalYAML(node *yaml.Node) error {
+	o.node = node
+	return nil
+}
+
+// decodeMapping decodes a mapping node into the struct pointed to by v.
+// The keys not matching the yaml tags of the struct fields are recorded in errs, to be reported
+// together with the other problems of the configuration.
+func decodeMapping(node *yaml.Node, v interface{}, errs *Errors) error {
+	if node.Kind != yaml.MappingNode {
+		*errs = append(*errs, &Error{Line: node.Line, Message: "a mapping is expected"})
+		return nil
+	}
+	known := map[string]bool{}
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name != "" {
+			known[name] = true
+		}
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if key := node.Content[i]; !known[key.Value] {
+			*errs = append(*errs, &Error{Line: key.Line, Message: fmt.Sprintf("unknown field %q", key.Value)})
+		}
+	}
+	return node.Decode(v)
+}
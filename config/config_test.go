@@ -0,0 +1,195 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/caeret/neo"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRegistry() *Registry {
+	write := func(s string) neo.Handler {
+		return func(c *neo.Context) error {
+			return c.Write(s)
+		}
+	}
+	tag := func(s string) MiddlewareFactory {
+		return func(Options) (neo.Handler, error) {
+			return func(c *neo.Context) error {
+				c.Response.Header().Add("X-Chain", s)
+				return nil
+			}, nil
+		}
+	}
+	return NewRegistry().
+		Handler("health", write("ok")).
+		Handler("user", func(c *neo.Context) error { return c.Write("user " + c.Param("id")) }).
+		Handler("tenant", func(c *neo.Context) error { return c.Write("tenant " + c.Param("tenant")) }).
+		Middleware("root", tag("root")).
+		Middleware("api", tag("api")).
+		Middleware("route", tag("route"))
+}
+
+func serve(router *neo.Router, method, url string, header ...string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, url, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	router.ServeHTTP(res, req)
+	return res
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("js"), 0644))
+
+	g, err := Parse([]byte(`
+middleware:
+  - root
+  - name: cors
+    options:
+      allowOrigins: "*"
+      maxAge: 1h
+routes:
+  - method: GET
+    path: /health
+    handler: health
+groups:
+  - prefix: /api
+    middleware: [api]
+    routes:
+      - method: GET,HEAD
+        path: /users/<id:\d+>
+        handler: user
+        name: user
+        middleware: [route]
+      - method: GET
+        path: /login
+        handler: health
+        skip: [api, root]
+    static:
+      - path: /assets
+        dir: ` + dir + `
+  - host: <tenant>.example.com
+    routes:
+      - method: GET
+        path: /
+        handler: tenant
+`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	router, err := g.Build(newTestRegistry())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	res := serve(router, "GET", "/health", "Origin", "http://example.com")
+	assert.Equal(t, "ok", res.Body.String())
+	assert.Equal(t, []string{"root"}, res.Header().Values("X-Chain"))
+	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+
+	res = serve(router, "GET", "/api/users/12")
+	assert.Equal(t, "user 12", res.Body.String())
+	assert.Equal(t, []string{"root", "api", "route"}, res.Header().Values("X-Chain"))
+	assert.Equal(t, "/api/users/12", router.Route("user").URL("id", 12))
+
+	res = serve(router, "GET", "/api/login")
+	assert.Equal(t, "ok", res.Body.String())
+	assert.Empty(t, res.Header().Values("X-Chain"))
+
+	res = serve(router, "GET", "/api/assets/app.js")
+	assert.Equal(t, "js", res.Body.String())
+
+	res = serve(router, "GET", "http://acme.example.com/")
+	assert.Equal(t, "tenant acme", res.Body.String())
+
+	res = serve(router, "GET", "/missing")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, []string{"root"}, res.Header().Values("X-Chain"))
+}
+
+func TestBuildJSON(t *testing.T) {
+	g, err := Parse([]byte(`{
+  "groups": [{
+    "prefix": "/v1",
+    "routes": [{"method": "GET", "path": "/health", "handler": "health"}]
+  }]
+}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	router, err := g.Build(newTestRegistry())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "ok", serve(router, "GET", "/v1/health").Body.String())
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	g, err := Parse([]byte(`routes:
+  - method: GET
+    path: /health
+    handler: missing
+  - method: FETCH
+    path: health
+    handler: health
+    color: red
+middleware:
+  - nope
+  - name: jwt
+groups:
+  - prefix: /api
+    groups:
+      - host: example.com
+  - host: <tenant:(>.example.com
+    routes:
+      - method: GET
+        path: /x/<id:[>
+        handler: health
+static:
+  - path: /assets
+`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = g.Build(newTestRegistry())
+	assert.Equal(t, `line 2: unknown handler "missing"
+line 5: path must start with a slash
+line 5: unknown method "FETCH"
+line 8: unknown field "color"
+line 10: unknown middleware "nope"
+line 11: middleware "jwt": the verification key is required
+line 15: host is only allowed for the top-level groups
+line 16: invalid pattern of parameter "tenant": error parsing regexp: missing closing ): `+"`(`"+`
+line 18: invalid pattern of parameter "id": error parsing regexp: missing closing ]: `+"`[`"+`
+line 22: dir is required`, err.Error())
+}
+
+func TestBuilderRegister(t *testing.T) {
+	b := &builder{registry: newTestRegistry(), router: neo.New()}
+	assert.True(t, b.register(3, func() {}))
+	assert.False(t, b.register(5, func() { panic("conflict") }))
+	assert.Equal(t, "line 5: conflict", b.errs.Error())
+}
+
+func TestLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "routes.yaml")
+	assert.Nil(t, os.WriteFile(filename, []byte("routes:\n  - method: GET\n    path: /health\n    handler: health\n"), 0644))
+	router, err := Load(filename, newTestRegistry())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "ok", serve(router, "GET", "/health").Body.String())
+	}
+
+	assert.Nil(t, os.WriteFile(filename, []byte("routes:\n  - method: GET\n    path: /health\n"), 0644))
+	_, err = Load(filename, newTestRegistry())
+	assert.Equal(t, filename+":2: handler is required", err.Error())
+
+	assert.Nil(t, os.WriteFile(filename, []byte("routes: [\n"), 0644))
+	_, err = Load(filename, newTestRegistry())
+	assert.Contains(t, err.Error(), filename+": yaml: line")
+}
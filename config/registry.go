@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/caeret/neo"
+	"github.com/caeret/neo/access"
+	"github.com/caeret/neo/auth"
+	"github.com/caeret/neo/cors"
+)
+
+// MiddlewareFactory creates a middleware handler from its options in the configuration.
+type MiddlewareFactory func(options Options) (neo.Handler, error)
+
+// Registry holds the named handlers and middleware factories that configurations refer to.
+type Registry struct {
+	handlers   map[string]neo.Handler
+	middleware map[string]MiddlewareFactory
+}
+
+// NewRegistry creates a Registry with the following built-in middleware factories:
+//
+//   - "logger": access.Logger writing to the standard logger.
+//   - "cors": cors.Handler with the options allowOrigins, allowCredentials, allowMethods, allowHeaders,
+//     exposeHeaders and maxAge (a duration such as "1h").
+//   - "jwt": auth.JWT with the options key (or keyEnv, the environment variable holding the key),
+//     realm and signingMethod.
+//
+// Static file mounts are served by file.Server.
+func NewRegistry() *Registry {
+	r := &Registry{
+		handlers:   map[string]neo.Handler{},
+		middleware: map[string]MiddlewareFactory{},
+	}
+	r.Middleware("logger", newLogger)
+	r.Middleware("cors", newCORS)
+	r.Middleware("jwt", newJWT)
+	return r
+}
+
+// Handler registers a handler with the given name and returns the registry itself.
+func (r *Registry) Handler(name string, handler neo.Handler) *Registry {
+	r.handlers[name] = handler
+	return r
+}
+
+// Middleware registers a middleware factory with the given name and returns the registry itself.
+// A factory registered with the name of a built-in one replaces it.
+func (r *Registry) Middleware(name string, factory MiddlewareFactory) *Registry {
+	r.middleware[name] = factory
+	return r
+}
+
+func newLogger(Options) (neo.Handler, error) {
+	return access.Logger(log.Printf), nil
+}
+
+func newCORS(options Options) (neo.Handler, error) {
+	var opts struct {
+		AllowOrigins     string        `yaml:"allowOrigins"`
+		AllowCredentials bool          `yaml:"allowCredentials"`
+		AllowMethods     string        `yaml:"allowMethods"`
+		AllowHeaders     string        `yaml:"allowHeaders"`
+		ExposeHeaders    string        `yaml:"exposeHeaders"`
+		MaxAge           time.Duration `yaml:"maxAge"`
+	}
+	if err := options.Decode(&opts); err != nil {
+		return nil, err
+	}
+	return cors.Handler(cors.Options{
+		AllowOrigins:     opts.AllowOrigins,
+		AllowCredentials: opts.AllowCredentials,
+		AllowMethods:     opts.AllowMethods,
+		AllowHeaders:     opts.AllowHeaders,
+		ExposeHeaders:    opts.ExposeHeaders,
+		MaxAge:           opts.MaxAge,
+	}), nil
+}
+
+func newJWT(options Options) (neo.Handler, error) {
+	var opts struct {
+		Key           string `yaml:"key"`
+		KeyEnv        string `yaml:"keyEnv"`
+		Realm         string `yaml:"realm"`
+		SigningMethod string `yaml:"signingMethod"`
+	}
+	if err := options.Decode(&opts); err != nil {
+		return nil, err
+	}
+	if opts.KeyEnv != "" {
+		opts.Key = os.Getenv(opts.KeyEnv)
+	}
+	if opts.Key == "" {
+		return nil, errors.New("the verification key is required")
+	}
+	return auth.JWT(opts.Key, auth.JWTOptions{
+		Realm:         opts.Realm,
+		SigningMethod: opts.SigningMethod,
+	}), nil
+}
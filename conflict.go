@@ -0,0 +1,225 @@
+package neo
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// ConflictKind is the kind of a RouteConflict.
+type ConflictKind int
+
+const (
+	// ConflictDuplicate means that a route has the same method, host and path as an earlier route,
+	// and neither route has matchers to tell them apart. The later route is never used.
+	ConflictDuplicate ConflictKind = iota
+	// ConflictShadowed means that every path matching a route also matches an earlier route,
+	// which takes precedence. The later route is never used.
+	ConflictShadowed
+	// ConflictAmbiguous means that some paths match both a route and an earlier route, and which one
+	// handles such a path depends only on the registration order.
+	ConflictAmbiguous
+)
+
+// String returns the name of the conflict kind.
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictDuplicate:
+		return "duplicate"
+	case ConflictShadowed:
+		return "shadowed"
+	default:
+		return "ambiguous"
+	}
+}
+
+// RouteConflict describes a conflict between two routes with the same method and host.
+type RouteConflict struct {
+	Kind ConflictKind
+	// Route is the route registered later.
+	Route *Route
+	// Other is the route registered earlier, which takes precedence over Route.
+	Other *Route
+}
+
+// Error returns the description of the conflict, including the source locations of both routes.
+func (c RouteConflict) Error() string {
+	var verb string
+	switch c.Kind {
+	case ConflictDuplicate:
+		verb = "duplicates"
+	case ConflictShadowed:
+		verb = "is shadowed by"
+	default:
+		verb = "is ambiguous with"
+	}
+	return fmt.Sprintf("route %v (%v) %v route %v (%v)", c.Route.describe(), c.Route.source, verb, c.Other.describe(), c.Other.source)
+}
+
+// Validate checks all routes for conflicts with the routes registered before them.
+// See Router.Strict for reporting the conflicts as soon as the routes are added.
+//
+// Conflicts are only reported between routes with the same method and host, and routes with matchers
+// or versions are not considered to take precedence over later routes. Ambiguous patterns are
+// detected by trying sample parameter values, so some ambiguities may be left undetected.
+func (r *Router) Validate() []RouteConflict {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var conflicts []RouteConflict
+	for i, route := range r.routes {
+		conflicts = append(conflicts, findConflicts(route, r.routes[:i])...)
+	}
+	return conflicts
+}
+
+// Source returns the source location ("file:line") where the route was registered.
+func (r *Route) Source() string {
+	return r.source
+}
+
+// describe returns the route method and path, including the host if any.
+func (r *Route) describe() string {
+	if host := r.Host(); host != "" {
+		return fmt.Sprintf("%q", r.method+" //"+host+r.Path())
+	}
+	return fmt.Sprintf("%q", r.String())
+}
+
+// findConflicts returns the conflicts between the route and the given routes registered earlier.
+func findConflicts(route *Route, earlier []*Route) []RouteConflict {
+	var conflicts []RouteConflict
+	for _, other := range earlier {
+		if other.method != route.method || other.group.host != route.group.host {
+			continue
+		}
+		if kind, ok := conflictKind(route, other); ok {
+			conflicts = append(conflicts, RouteConflict{Kind: kind, Route: route, Other: other})
+		}
+	}
+	return conflicts
+}
+
+// conflictKind determines the kind of the conflict between the route and an earlier route with the same method and host.
+// For routes with optional segments, the first conflict between their paths is reported.
+func conflictKind(route, other *Route) (ConflictKind, bool) {
+	for _, sp := range route.storePaths() {
+		for _, osp := range other.storePaths() {
+			if kind, ok := pathConflictKind(route, other, sp.path, osp.path); ok {
+				return kind, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// pathConflictKind determines the kind of the conflict between a store path of the route and
+// a store path of an earlier route.
+func pathConflictKind(route, other *Route, path, otherPath string) (ConflictKind, bool) {
+	if path == otherPath {
+		v, ov := route.group.version, other.group.version
+		switch {
+		case len(route.matchers) > 0 || len(other.matchers) > 0:
+			return 0, false
+		case v == nil && ov == nil, v != nil && ov != nil && v.Name == ov.Name:
+			return ConflictDuplicate, true
+		}
+		return 0, false
+	}
+	if len(other.matchers) > 0 || other.group.version != nil {
+		// the earlier route does not always take precedence
+		return 0, false
+	}
+	if !strings.Contains(path, "<") {
+		if patternRegexp(otherPath).MatchString(path) {
+			return ConflictShadowed, true
+		}
+		return 0, false
+	}
+	if !strings.Contains(otherPath, "<") {
+		// a static route registered earlier is an exception to a parametric route
+		return 0, false
+	}
+	if anonymizeParams(path) == anonymizeParams(otherPath) {
+		return ConflictShadowed, true
+	}
+	regex, otherRegex := patternRegexp(path), patternRegexp(otherPath)
+	for _, sample := range samplePaths(path) {
+		if otherRegex.MatchString(sample) {
+			return ConflictAmbiguous, true
+		}
+	}
+	for _, sample := range samplePaths(otherPath) {
+		if regex.MatchString(sample) {
+			return ConflictAmbiguous, true
+		}
+	}
+	return 0, false
+}
+
+// paramToken matches a parameter token in a route path.
+var paramToken = regexp.MustCompile(`<([^:>]*)(?::([^>]*))?>`)
+
+// patternRegexp converts a route path into a regular expression matching the same paths as the route store.
+func patternRegexp(path string) *regexp.Regexp {
+	var buf strings.Builder
+	buf.WriteString("^")
+	last := 0
+	for _, m := range paramToken.FindAllStringSubmatchIndex(path, -1) {
+		buf.WriteString(regexp.QuoteMeta(path[last:m[0]]))
+		if m[4] >= 0 {
+			buf.WriteString("(?:" + path[m[4]:m[5]] + ")")
+		} else {
+			buf.WriteString("[^/]*")
+		}
+		last = m[1]
+	}
+	buf.WriteString(regexp.QuoteMeta(path[last:]) + "$")
+	regex, err := regexp.Compile(buf.String())
+	if err != nil {
+		return regexp.MustCompile("$^")
+	}
+	return regex
+}
+
+// anonymizeParams removes the parameter names from a route path.
+func anonymizeParams(path string) string {
+	return paramToken.ReplaceAllString(path, "<:$2>")
+}
+
+// sampleValues are the parameter values tried when looking for paths matching two routes.
+var sampleValues = []string{"0", "1", "12", "a", "abc", "A", "x1", "a-b", "a_b", "a.b", "a b"}
+
+// samplePaths returns paths matching the route path, with all parameters set to each of the sample values.
+func samplePaths(path string) []string {
+	regex := patternRegexp(path)
+	var samples []string
+	for _, value := range sampleValues {
+		if sample := paramToken.ReplaceAllLiteralString(path, value); regex.MatchString(sample) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// packageDir is the directory of the source files of this package.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callerSource returns the location ("file:line") of the first caller outside this package.
+func callerSource() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%v:%v", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
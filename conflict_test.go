@@ -0,0 +1,77 @@
+package neo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterValidate(t *testing.T) {
+	router := New()
+	router.Get("/users/me")
+	router.Get("/users/<id>")
+	router.Get("/users/<name>")
+	router.Get("/users/admin")
+	router.Get("/users")
+	router.Post("/users")
+	router.Get("/users")
+	router.Get("/items/<id:\\d+>")
+	router.Get("/items/<slug:[a-z]+>")
+	router.Get("/items/<code:[a-z0-9]+>")
+	router.Get("/files/*")
+	router.Get("/files/readme")
+	router.Get("/search").Match(MatchQuery("q"))
+	router.Get("/search")
+	router.Host("example.com").Get("/users")
+	router.Version("v1").Get("/orders")
+	router.Version("v2").Get("/orders")
+
+	var conflicts []string
+	for _, c := range router.Validate() {
+		conflicts = append(conflicts, fmt.Sprintf("%v %v %v", c.Kind, c.Route, c.Other))
+	}
+	assert.Equal(t, []string{
+		"shadowed GET /users/<name> GET /users/<id>",
+		"shadowed GET /users/admin GET /users/<id>",
+		"shadowed GET /users/admin GET /users/<name>",
+		"duplicate GET /users GET /users",
+		"ambiguous GET /items/<code:[a-z0-9]+> GET /items/<id:\\d+>",
+		"ambiguous GET /items/<code:[a-z0-9]+> GET /items/<slug:[a-z]+>",
+		"shadowed GET /files/readme GET /files/*",
+	}, conflicts)
+}
+
+func TestRouteConflictError(t *testing.T) {
+	router := New()
+	router.Get("/users/<id>")
+	router.Host("example.com").Get("/users")
+	router.Host("example.com").Get("/users")
+	router.Get("/users/<name>")
+	conflicts := router.Validate()
+	if !assert.Len(t, conflicts, 2) {
+		return
+	}
+	assert.True(t, strings.HasSuffix(router.Routes()[0].Source(), "conflict_test.go:48"), router.Routes()[0].Source())
+	assert.Equal(t, fmt.Sprintf(`route "GET //example.com/users" (%v) duplicates route "GET //example.com/users" (%v)`,
+		router.Routes()[2].Source(), router.Routes()[1].Source()), conflicts[0].Error())
+	assert.Contains(t, conflicts[1].Error(), `route "GET /users/<name>" (`)
+	assert.Contains(t, conflicts[1].Error(), `conflict_test.go:51) is shadowed by route "GET /users/<id>" (`)
+}
+
+func TestRouterStrict(t *testing.T) {
+	router := New()
+	router.Strict = true
+	router.Get("/users/me")
+	router.Get("/users/<id>")
+	defer func() {
+		conflict, ok := recover().(RouteConflict)
+		if assert.True(t, ok) {
+			assert.Equal(t, ConflictDuplicate, conflict.Kind)
+			assert.Equal(t, "GET /users/<id>", conflict.Other.String())
+		}
+		assert.Len(t, router.Routes(), 2)
+	}()
+	router.Get("/users/<id>")
+}
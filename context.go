@@ -18,10 +18,14 @@ type Context struct {
 	router   *Router
 	pnames   []string               // list of route parameter names
 	pvalues  []string               // list of parameter values corresponding to pnames
+	ptyped   []interface{}          // list of typed parameter values corresponding to pnames. nil for untyped ones.
 	data     map[string]interface{} // data items managed by Get and Set
 	index    int                    // the index of the currently executing handler in handlers
 	handlers []Handler              // the handlers associated with the current route
 	writer   DataWriter
+	resp     responseWriter // the wrapper of the response writer given to init
+	allow    *allowEntry    // the methods allowed for the request path when responding with 405
+	path     string         // the request path matched against the routes, after being normalized
 }
 
 // NewContext creates a new Context object with the given response, request, and the handlers.
@@ -156,7 +160,7 @@ func (c *Context) Abort() {
 // Parameter values will be properly URL encoded.
 // The method returns an empty string if the URL creation fails.
 func (c *Context) URL(route string, pairs ...interface{}) string {
-	if r := c.router.namedRoutes[route]; r != nil {
+	if r := c.router.Route(route); r != nil {
 		return r.URL(pairs...)
 	}
 	return ""
@@ -187,15 +191,84 @@ func (c *Context) RealIP() string {
 // and find a matching reader from DataReaders to read the request data.
 // If there is no match or if the request is a GET request, it will use DefaultFormDataReader
 // to read the request data.
+// If the data is a pointer to a struct, the route parameters, the request headers and cookies are then bound
+// into the fields tagged with `param`, `header`, and `cookie`, respectively. A reading or binding failure
+// results in an http.StatusBadRequest HTTPError wrapping the cause.
+// Finally, the data is validated by Validate, which returns a *ValidationError listing every violated rule.
 func (c *Context) Read(data interface{}) error {
+	if err := c.read(c.reader(), data, false); err != nil {
+		return err
+	}
+	return Validate(data)
+}
+
+// read populates data with the request data read by the reader. If data is a pointer to a struct, the fields
+// tagged with `param`, `header`, and `cookie`, and those tagged with `query` if query is true, are bound as well.
+// Any error is returned as an http.StatusBadRequest HTTPError.
+func (c *Context) read(reader DataReader, data interface{}, query bool) error {
+	if err := reader.Read(c.Request, data); err != nil {
+		return badRequest(err)
+	}
+	if !isStructPointer(data) {
+		return nil
+	}
+	if query {
+		values := c.Request.URL.Query()
+		src := valueSource{tag: queryTag, kind: "query parameter", lookup: func(name string) ([]string, bool) {
+			value, ok := values[name]
+			return value, ok
+		}}
+		if err := readData(src, data); err != nil {
+			return badRequest(err)
+		}
+	}
+	if err := c.bindParams(data); err != nil {
+		return badRequest(err)
+	}
+	if _, ok := reader.(*FormDataReader); ok {
+		// the headers and cookies have been read by the reader
+		return nil
+	}
+	if err := readHeaderCookieData(c.Request, data); err != nil {
+		return badRequest(err)
+	}
+	return nil
+}
+
+// BindParams populates the struct pointed to by data with the route parameters.
+// Each struct field tagged with `param:"name"` receives the value of the named parameter.
+// The values are converted into the field types the same way as ReadFormData does, including
+// the support of encoding.TextUnmarshaler. An http.StatusBadRequest HTTPError is returned
+// if a value cannot be converted.
+func (c *Context) BindParams(data interface{}) error {
+	if err := c.bindParams(data); err != nil {
+		return badRequest(err)
+	}
+	return nil
+}
+
+// bindParams populates the struct pointed to by data with the route parameters.
+func (c *Context) bindParams(data interface{}) error {
+	src := valueSource{tag: paramTag, kind: "path parameter", lookup: func(name string) ([]string, bool) {
+		for i, n := range c.pnames {
+			if n == name {
+				return []string{c.pvalues[i]}, true
+			}
+		}
+		return nil, false
+	}}
+	return readData(src, data)
+}
+
+// reader returns the DataReader that Read uses for the current request.
+func (c *Context) reader() DataReader {
 	if c.Request.Method != "GET" {
 		t := getContentType(c.Request)
 		if reader, ok := DataReaders[t]; ok {
-			return reader.Read(c.Request, data)
+			return reader
 		}
 	}
-
-	return DefaultFormDataReader.Read(c.Request, data)
+	return DefaultFormDataReader
 }
 
 // Write writes the given data of arbitrary type to the response.
@@ -218,6 +291,12 @@ func (c *Context) SetDataWriter(writer DataWriter) {
 	writer.SetHeader(c.Response)
 }
 
+// Written returns whether the response status code or body has already been sent.
+// Once the response is written, the status code and the headers can no longer be changed.
+func (c *Context) Written() bool {
+	return c.resp.written
+}
+
 // Context returns the request context.
 func (c *Context) Context() context.Context {
 	return c.Request.Context()
@@ -226,12 +305,27 @@ func (c *Context) Context() context.Context {
 // init sets the request and response of the context and resets all other properties.
 func (c *Context) init(response http.ResponseWriter, request *http.Request) {
 	c.Response = response
+	if response != nil {
+		c.resp.reset(response)
+		c.Response = c.resp.wrapper()
+	}
 	c.Request = request
 	c.data = nil
 	c.index = -1
+	c.ptyped = c.ptyped[:0]
+	c.allow = nil
+	c.path = ""
 	c.writer = DefaultDataWriter
 }
 
+// routePath returns the request path matched against the routes.
+func (c *Context) routePath() string {
+	if c.path != "" {
+		return c.path
+	}
+	return c.router.normalizeRequestPath(c.Request.URL.Path)
+}
+
 func getContentType(req *http.Request) string {
 	t := req.Header.Get("Content-Type")
 	for i, c := range t {
@@ -164,3 +164,95 @@ func testNormalHandler(tag string) Handler {
 		return nil
 	}
 }
+
+func TestContextBindParams(t *testing.T) {
+	c := NewContext(nil, nil)
+	c.pnames = []string{"id", "slug", "tu"}
+	c.pvalues = []string{"12", "hello-world", "abc"}
+
+	var data struct {
+		ID   int    `param:"id"`
+		Slug string `param:"slug"`
+		TU   *TU    `param:"tu"`
+		Name string
+	}
+	assert.Nil(t, c.BindParams(&data))
+	assert.Equal(t, 12, data.ID)
+	assert.Equal(t, "hello-world", data.Slug)
+	assert.Equal(t, "TU_abc", data.TU.UValue)
+	assert.Equal(t, "", data.Name)
+
+	c.pvalues[0] = "abc"
+	err := c.BindParams(&data)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(HTTPError).StatusCode())
+		assert.Equal(t, `invalid path parameter "id": strconv.ParseInt: parsing "abc": invalid syntax`, err.Error())
+	}
+}
+
+func TestContextReadParams(t *testing.T) {
+	router := New()
+	router.Put(`/users/<id:\d+>`, func(c *Context) error {
+		var data struct {
+			ID   int    `param:"id"`
+			Name string `json:"name"`
+		}
+		if err := c.Read(&data); err != nil {
+			return err
+		}
+		return c.Write(fmt.Sprintf("%v:%v", data.ID, data.Name))
+	})
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/users/12", strings.NewReader(`{"name":"abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "12:abc", res.Body.String())
+}
+
+func TestContextWritten(t *testing.T) {
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/", nil)
+	c := NewContext(res, req)
+	assert.False(t, c.Written())
+	c.Response.Header().Set("X-Test", "abc")
+	assert.False(t, c.Written())
+	c.Response.WriteHeader(http.StatusCreated)
+	assert.True(t, c.Written())
+	c.Response.Write([]byte("abc"))
+	assert.Equal(t, http.StatusCreated, c.resp.status)
+
+	res = httptest.NewRecorder()
+	c.init(res, req)
+	assert.False(t, c.Written())
+	c.Response.(http.Flusher).Flush()
+	assert.True(t, c.Written())
+	assert.True(t, res.Flushed)
+	assert.Equal(t, http.ResponseWriter(res), c.resp.Unwrap())
+	_, ok := c.Response.(http.Hijacker)
+	assert.False(t, ok)
+	_, ok = c.Response.(http.Pusher)
+	assert.False(t, ok)
+
+	pres := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c.init(pres, req)
+	_, ok = c.Response.(http.Hijacker)
+	assert.False(t, ok)
+	if assert.Implements(t, (*http.Pusher)(nil), c.Response) {
+		assert.Nil(t, c.Response.(http.Pusher).Push("/app.js", nil))
+		assert.Equal(t, []string{"/app.js"}, pres.pushed)
+	}
+	c.Response.(http.Flusher).Flush()
+	assert.True(t, c.Written())
+	assert.Equal(t, http.ResponseWriter(pres), c.Response.(interface{ Unwrap() http.ResponseWriter }).Unwrap())
+}
+
+// pushRecorder is a ResponseRecorder supporting HTTP/2 server pushes.
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (r *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	r.pushed = append(r.pushed, target)
+	return nil
+}
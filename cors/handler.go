@@ -56,6 +56,10 @@ var AllowAll = Options{
 }
 
 // Handler creates a routing handler that adds appropriate CORS headers according to the specified options and the request.
+//
+// A preflight request is aborted after the CORS headers are added. The requested method is rejected if the router
+// knows the methods allowed for the request path (see neo.Context.AllowedMethods) and the method is not one of them.
+// The handler can be used with the automatic OPTIONS responses of a route group via neo.RouteGroup.OptionsHandler.
 func Handler(opts Options) neo.Handler {
 	opts.init()
 
@@ -72,6 +76,11 @@ func Handler(opts Options) neo.Handler {
 				// the request is outside the scope of CORS
 				return
 			}
+			if allowed := c.AllowedMethods(); allowed != "" && !isMethodListed(allowed, method) {
+				// the router has no route with the requested method for the request path
+				c.Abort()
+				return
+			}
 			headers := c.Request.Header.Get(headerRequestHeaders)
 			opts.setPreflightHeaders(origin, method, headers, c.Response.Header())
 			c.Abort()
@@ -181,3 +190,13 @@ func buildAllowMap(s string, caseSensitive bool) map[string]bool {
 	}
 	return m
 }
+
+// isMethodListed reports whether the method is in the list of methods separated by commas, such as an Allow header.
+func isMethodListed(list, method string) bool {
+	for _, m := range strings.Split(list, ",") {
+		if strings.TrimSpace(m) == method {
+			return true
+		}
+	}
+	return false
+}
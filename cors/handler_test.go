@@ -227,3 +227,31 @@ func TestHandlers(t *testing.T) {
 	assert.Nil(t, h(c))
 	assert.Equal(t, "", res.Header().Get(headerAllowOrigin))
 }
+
+func TestHandlerWithOptionsHandler(t *testing.T) {
+	router := neo.New()
+	api := router.Group("/api")
+	api.OptionsHandler(Handler(Options{
+		AllowOrigins: "https://example.com",
+		AllowMethods: "*",
+	}))
+	api.Put("/users/<id>", func(c *neo.Context) error { return nil })
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("OPTIONS", "/api/users/1", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "https://example.com", res.Header().Get(headerAllowOrigin))
+	assert.Equal(t, "PUT", res.Header().Get(headerAllowMethods))
+
+	// the router has no DELETE route for the path
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/api/users/1", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "", res.Header().Get(headerAllowOrigin))
+	assert.Equal(t, "", res.Header().Get(headerAllowMethods))
+}
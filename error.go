@@ -4,7 +4,13 @@
 
 package neo
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+)
 
 // HTTPError represents an HTTP error with HTTP status code and error message
 type HTTPError interface {
@@ -15,8 +21,31 @@ type HTTPError interface {
 
 // Error contains the error information reported by calling Context.Error().
 type httpError struct {
-	Status  int    `json:"status" xml:"status"`
-	Message string `json:"message" xml:"message"`
+	Status  int                    `json:"status" xml:"status"`
+	Message string                 `json:"message" xml:"message"`
+	Code    string                 `json:"code,omitempty" xml:"code,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty" xml:"-"`
+	cause   error
+	stack   []uintptr
+}
+
+// HTTPErrorOption customizes the HTTPError created by WrapHTTPError.
+type HTTPErrorOption func(*httpError)
+
+// WithErrorCode sets the application-specific error code of the HTTPError.
+// The code is included in the serialized error as "code".
+func WithErrorCode(code string) HTTPErrorOption {
+	return func(e *httpError) {
+		e.Code = code
+	}
+}
+
+// WithErrorDetails sets the additional details of the HTTPError.
+// The details are included in the serialized error as "details".
+func WithErrorDetails(details map[string]interface{}) HTTPErrorOption {
+	return func(e *httpError) {
+		e.Details = details
+	}
 }
 
 // NewHTTPError creates a new HttpError instance.
@@ -24,9 +53,43 @@ type httpError struct {
 // to generate the message based on the status code.
 func NewHTTPError(status int, message ...string) HTTPError {
 	if len(message) > 0 {
-		return &httpError{status, message[0]}
+		return newHTTPError(status, nil, message[0])
+	}
+	return newHTTPError(status, nil, http.StatusText(status))
+}
+
+// WrapHTTPError creates a new HTTPError caused by the given error.
+// The cause can be retrieved via errors.Unwrap and is matched by errors.Is and errors.As,
+// but it is never exposed in the error message, which is meant for clients.
+// If the message is empty, http.StatusText() of the status code is used.
+// The cause may be nil when only the options are needed.
+//
+// For 5xx status codes, the call stack is captured when the error is created.
+// The cause and the call stack are printed when the error is formatted with "%+v".
+//
+//	if err := db.Find(&user, id); err != nil {
+//	    return neo.WrapHTTPError(http.StatusNotFound, err, "user not found", neo.WithErrorCode("user_not_found"))
+//	}
+func WrapHTTPError(status int, err error, message string, opts ...HTTPErrorOption) HTTPError {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	e := newHTTPError(status, err, message)
+	for _, opt := range opts {
+		opt(e)
 	}
-	return &httpError{status, http.StatusText(status)}
+	return e
+}
+
+func newHTTPError(status int, cause error, message string) *httpError {
+	e := &httpError{Status: status, Message: message, cause: cause}
+	if status >= http.StatusInternalServerError {
+		var pcs [32]uintptr
+		// skip runtime.Callers, newHTTPError and the exported constructor
+		n := runtime.Callers(3, pcs[:])
+		e.stack = pcs[:n]
+	}
+	return e
 }
 
 // Error returns the error message.
@@ -38,3 +101,43 @@ func (e *httpError) Error() string {
 func (e *httpError) StatusCode() int {
 	return e.Status
 }
+
+// Unwrap returns the cause of the error.
+func (e *httpError) Unwrap() error {
+	return e.cause
+}
+
+// Format implements fmt.Formatter. The "%+v" verb prints the message followed by the cause
+// and the call stack captured at creation time. Other verbs print the message only.
+func (e *httpError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, e.Message)
+		if e.cause != nil {
+			fmt.Fprintf(s, ": %+v", e.cause)
+		}
+		if len(e.stack) > 0 {
+			frames := runtime.CallersFrames(e.stack)
+			for {
+				frame, more := frames.Next()
+				fmt.Fprintf(s, "\n%s:%d", frame.File, frame.Line)
+				if !more {
+					break
+				}
+			}
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Message)
+	default:
+		io.WriteString(s, e.Message)
+	}
+}
+
+// badRequest converts the given error into an http.StatusBadRequest HTTPError unless it is an HTTPError already.
+func badRequest(err error) error {
+	var httpError HTTPError
+	if errors.As(err, &httpError) {
+		return err
+	}
+	return WrapHTTPError(http.StatusBadRequest, err, err.Error())
+}
@@ -6,7 +6,10 @@ package neo
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,3 +27,32 @@ func TestNewHttpError(t *testing.T) {
 	s, _ := json.Marshal(e)
 	assert.Equal(t, `{"status":404,"message":"abc"}`, string(s))
 }
+
+func TestWrapHTTPError(t *testing.T) {
+	cause := errors.New("record not found")
+	e := WrapHTTPError(http.StatusNotFound, cause, "user not found",
+		WithErrorCode("user_not_found"), WithErrorDetails(map[string]interface{}{"id": 12}))
+	assert.Equal(t, http.StatusNotFound, e.StatusCode())
+	assert.Equal(t, "user not found", e.Error())
+	assert.Equal(t, cause, errors.Unwrap(e))
+	assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", e), cause))
+	assert.Equal(t, "user not found: record not found", fmt.Sprintf("%+v", e))
+	assert.Equal(t, "user not found", fmt.Sprintf("%v", e))
+
+	var he HTTPError
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", e), &he))
+	assert.Equal(t, e, he)
+
+	s, _ := json.Marshal(e)
+	assert.Equal(t, `{"status":404,"message":"user not found","code":"user_not_found","details":{"id":12}}`, string(s))
+
+	e = WrapHTTPError(http.StatusInternalServerError, cause, "")
+	assert.Equal(t, "Internal Server Error", e.Error())
+	assert.True(t, strings.HasPrefix(fmt.Sprintf("%+v", e), "Internal Server Error: record not found\n"))
+	assert.Contains(t, fmt.Sprintf("%+v", e), "error_test.go:")
+	assert.Contains(t, fmt.Sprintf("%+v", NewHTTPError(http.StatusBadGateway)), "error_test.go:")
+	assert.NotContains(t, fmt.Sprintf("%+v", NewHTTPError(http.StatusBadRequest)), "error_test.go:")
+
+	p := ProblemFrom(WrapHTTPError(http.StatusConflict, nil, "exists", WithErrorCode("exists")))
+	assert.Equal(t, map[string]interface{}{"code": "exists"}, p.Extensions)
+}
@@ -6,17 +6,20 @@
 package fault
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/caeret/neo"
 )
 
 // ErrorHandler returns a handler that handles errors returned by the handlers following this one.
-// If the error implements mat.HTTPError, the handler will set the HTTP status code accordingly.
+// If the error or any error in its chain implements mat.HTTPError, the handler will set the HTTP status code accordingly.
 // Otherwise the HTTP status is set as http.StatusInternalServerError. The handler will also write the error
 // as the response body.
 //
 // A log function can be provided to log a message whenever an error is handled. If nil, no message will be logged.
+// The error is logged with the "%+v" verb so that the cause and the call stack of errors created by
+// neo.WrapHTTPError are included.
 //
 // An optional error conversion function can also be provided to convert an error into a normalized one
 // before sending it to the response.
@@ -38,7 +41,7 @@ func ErrorHandler(logf LogFunc, errorf ...ConvertErrorFunc) neo.Handler {
 		}
 
 		if logf != nil {
-			logf("%v", err)
+			logf("%+v", err)
 		}
 
 		if len(errorf) > 0 {
@@ -52,12 +55,29 @@ func ErrorHandler(logf LogFunc, errorf ...ConvertErrorFunc) neo.Handler {
 	}
 }
 
+// Problem is a ConvertErrorFunc that converts errors into neo.ProblemDetails by calling neo.ProblemFrom,
+// so that the errors are written as RFC 9457 problem details.
+//
+//	r.Use(fault.ErrorHandler(log.Printf, fault.Problem))
+func Problem(c *neo.Context, err error) error {
+	return neo.ProblemFrom(err)
+}
+
 // writeError writes the error to the response.
-// If the error implements HTTPError, it will set the HTTP status as the result of the StatusCode() call of the error.
+// A neo.ProblemDetails is written in the problem details format negotiated with the request.
+// If an HTTPError is found in the error chain, it will set the HTTP status as the result of the StatusCode() call
+// of that error and write that error.
 // Otherwise, the HTTP status will be set as http.StatusInternalServerError.
 func writeError(c *neo.Context, err error) {
-	if httpError, ok := err.(neo.HTTPError); ok {
+	var problem *neo.ProblemDetails
+	if errors.As(err, &problem) {
+		neo.WriteProblem(c, problem)
+		return
+	}
+	var httpError neo.HTTPError
+	if errors.As(err, &httpError) {
 		c.Response.WriteHeader(httpError.StatusCode())
+		err = httpError
 	} else {
 		c.Response.WriteHeader(http.StatusInternalServerError)
 	}
@@ -3,6 +3,7 @@ package fault
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -73,3 +74,52 @@ func Test_writeError(t *testing.T) {
 func convertError(c *neo.Context, err error) error {
 	return errors.New("123")
 }
+
+func TestProblem(t *testing.T) {
+	h := ErrorHandler(nil, Problem)
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/", nil)
+	c := neo.NewContext(res, req, h, handler1, handler2)
+	assert.Nil(t, c.Next())
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Equal(t, neo.MIME_PROBLEM_JSON, res.Header().Get("Content-Type"))
+	assert.Equal(t, `{"type":"about:blank","title":"Internal Server Error","status":500}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/", nil)
+	req.Header.Set("Accept", "application/xml")
+	c = neo.NewContext(res, req)
+	writeError(c, neo.NewProblem(http.StatusNotFound, "xyz"))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, neo.MIME_PROBLEM_XML, res.Header().Get("Content-Type"))
+	assert.Equal(t, `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Not Found</title><status>404</status><detail>xyz</detail></problem>`, res.Body.String())
+}
+
+func TestProblemXMLDetails(t *testing.T) {
+	h := ErrorHandler(nil, Problem)
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/", nil)
+	req.Header.Set("Accept", "application/xml")
+	c := neo.NewContext(res, req, h, func(c *neo.Context) error {
+		return neo.WrapHTTPError(http.StatusConflict, errors.New("duplicate key"), "user exists",
+			neo.WithErrorDetails(map[string]interface{}{"field": "email"}))
+	})
+	assert.Nil(t, c.Next())
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Equal(t, neo.MIME_PROBLEM_XML, res.Header().Get("Content-Type"))
+	assert.Equal(t, `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Conflict</title><status>409</status><detail>user exists</detail><details><field>email</field></details></problem>`, res.Body.String())
+}
+
+func TestErrorHandlerWrapped(t *testing.T) {
+	var buf bytes.Buffer
+	h := ErrorHandler(getLogger(&buf))
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/", nil)
+	c := neo.NewContext(res, req, h, func(c *neo.Context) error {
+		return neo.WrapHTTPError(http.StatusNotFound, fmt.Errorf("loading user: %w", errors.New("no rows")), "user not found")
+	})
+	assert.Nil(t, c.Next())
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, "user not found", res.Body.String())
+	assert.Equal(t, "user not found: loading user: no rows", buf.String())
+}
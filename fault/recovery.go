@@ -21,7 +21,7 @@ type (
 // Recovery can be considered as a combination of ErrorHandler and PanicHandler.
 //
 // The handler will recover from panics and render the recovered error or the error returned by a handler.
-// If the error implements mat.HTTPError, the handler will set the HTTP status code accordingly.
+// If the error or any error in its chain implements mat.HTTPError, the handler will set the HTTP status code accordingly.
 // Otherwise the HTTP status is set as http.StatusInternalServerError. The handler will also write the error
 // as the response body.
 //
@@ -43,7 +43,7 @@ func Recovery(logf LogFunc, errorf ...ConvertErrorFunc) neo.Handler {
 	return func(c *neo.Context) error {
 		if err := handlePanic(c); err != nil {
 			if logf != nil {
-				logf("%v", err)
+				logf("%+v", err)
 			}
 			if len(errorf) > 0 {
 				err = errorf[0](c, err)
@@ -5,8 +5,6 @@
 package neo
 
 import (
-	"reflect"
-	"runtime"
 	"strings"
 )
 
@@ -15,6 +13,10 @@ type RouteGroup struct {
 	prefix   string
 	router   *Router
 	handlers []Handler
+	names    []string    // the names of the handlers, used to skip named middleware
+	host     *hostRoutes // the host restriction of the routes. nil if the routes match any host.
+	version  *APIVersion // the API version of the routes. nil if the routes are not versioned.
+	options  []Handler   // the handlers responding to OPTIONS requests. nil if OptionsResponder is used.
 }
 
 // newRouteGroup creates a new RouteGroup with the given path prefix, router, and handlers.
@@ -23,6 +25,7 @@ func newRouteGroup(prefix string, router *Router, handlers []Handler) *RouteGrou
 		prefix:   prefix,
 		router:   router,
 		handlers: handlers,
+		names:    handlerNames(handlers),
 	}
 }
 
@@ -78,6 +81,10 @@ func (rg *RouteGroup) Any(path string, handlers ...Handler) *Route {
 
 // To adds a route to the router with the given HTTP methods, route path, and handlers.
 // Multiple HTTP methods should be separated by commas (without any surrounding spaces).
+//
+// Besides the "<name:regex>" parameter tokens, the route path may end with a named wildcard, such as
+// "/files/<path...>", which matches the rest of the path including slashes. Segments enclosed in square
+// brackets, such as "/posts[/<page:\d+>]", are optional, and the route matches the path with or without them.
 func (rg *RouteGroup) To(methods, path string, handlers ...Handler) *Route {
 	mm := strings.Split(methods, ",")
 	if len(mm) == 1 {
@@ -97,10 +104,14 @@ func (rg *RouteGroup) To(methods, path string, handlers ...Handler) *Route {
 // with the current group.
 func (rg *RouteGroup) Group(prefix string, handlers ...Handler) *RouteGroup {
 	if len(handlers) == 0 {
-		handlers = make([]Handler, len(rg.handlers))
-		copy(handlers, rg.handlers)
+		g := newRouteGroup(rg.prefix+prefix, rg.router, nil)
+		g.handlers, g.names = combineHandlers(rg.handlers, nil), combineNames(rg.names, nil)
+		g.host, g.version, g.options = rg.host, rg.version, rg.options
+		return g
 	}
-	return newRouteGroup(rg.prefix+prefix, rg.router, handlers)
+	g := newRouteGroup(rg.prefix+prefix, rg.router, handlers)
+	g.host, g.version, g.options = rg.host, rg.version, rg.options
+	return g
 }
 
 // With creates a RouteGroup with an empty route path prefix and handlers.
@@ -108,10 +119,11 @@ func (rg *RouteGroup) Group(prefix string, handlers ...Handler) *RouteGroup {
 // The new group will inherit the handlers registered
 // with the current group and provided handlers.
 func (rg *RouteGroup) With(handlers ...Handler) *RouteGroup {
-	newHandlers := make([]Handler, len(rg.handlers)+len(handlers))
-	copy(newHandlers, rg.handlers)
-	copy(newHandlers[len(rg.handlers):], handlers)
-	return newRouteGroup(rg.prefix, rg.router, newHandlers)
+	g := newRouteGroup(rg.prefix, rg.router, nil)
+	g.handlers = combineHandlers(rg.handlers, handlers)
+	g.names = combineNames(rg.names, handlerNames(handlers))
+	g.host, g.version, g.options = rg.host, rg.version, rg.options
+	return g
 }
 
 // Provide adds routes to the group by provided func
@@ -124,27 +136,45 @@ func (rg *RouteGroup) Provide(fn func(*RouteGroup)) *RouteGroup {
 // These handlers will be shared by all routes belong to this group and its subgroups.
 func (rg *RouteGroup) Use(handlers ...Handler) {
 	rg.handlers = append(rg.handlers, handlers...)
+	rg.names = append(rg.names, handlerNames(handlers)...)
 }
 
 func (rg *RouteGroup) add(method, path string, handlers []Handler) *Route {
-	handler := ""
-	if n := len(handlers); n > 0 {
-		handler = runtime.FuncForPC(reflect.ValueOf(handlers[n-1]).Pointer()).Name()
-	}
 	r := rg.newRoute(method, path)
-	r.handler = handler
-	rg.router.addRoute(r, combineHandlers(rg.handlers, handlers))
+	r.handler = handlerName(handlers)
+	r.groupHandlers, r.groupNames = rg.handlers, rg.names
+	r.handlers = combineHandlers(rg.handlers, handlers)
+	r.names = combineNames(rg.names, handlerNames(handlers))
+	rg.router.addRoute(r)
 	return r
 }
 
+// handlerName returns the function name of the last handler. An empty string is returned if there is no handler.
+func handlerName(handlers []Handler) string {
+	if n := len(handlers); n > 0 {
+		return handlerNames(handlers[n-1:])[0]
+	}
+	return ""
+}
+
 // newRoute creates a new Route with the given route path and route group.
 func (rg *RouteGroup) newRoute(method, path string) *Route {
 	return &Route{
 		group:    rg,
 		method:   method,
 		path:     path,
-		template: buildURLTemplate(rg.prefix + path),
+		template: buildURLTemplate(rg.hostPrefix() + rg.prefix + path),
+		source:   callerSource(),
+	}
+}
+
+// hostPrefix returns the network-path reference prefix (e.g. "//example.com") of the URLs of the group routes.
+// An empty string is returned if the group routes match any host.
+func (rg *RouteGroup) hostPrefix() string {
+	if rg.host == nil {
+		return ""
 	}
+	return "//" + rg.host.pattern
 }
 
 // combineHandlers merges two lists of handlers into a new list.
@@ -0,0 +1,149 @@
+package neo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hostRoutes stores the routes that only match requests whose Host header matches a host pattern.
+type hostRoutes struct {
+	pattern string                // the host pattern, such as "<tenant>.example.com"
+	regex   *regexp.Regexp        // the regular expression compiled from the pattern
+	pnames  []string              // the names of the parameters in the pattern
+	indexes []int                 // the indexes of the submatches holding the parameter values
+	stores  map[string]routeStore // the route stores indexed by HTTP methods
+}
+
+// Host creates a RouteGroup whose routes only match requests with a Host header matching the given pattern.
+//
+// Similar to route paths, the pattern may contain parameter tokens in the format of "<name>" or "<name:pattern>".
+// A "<name>" token matches a single host label, i.e., a string without dots. Host names are matched
+// case-insensitively, and the port in the Host header is ignored. The values of the host parameters are available
+// via Context.Param together with the route parameters, and Route.URL generates URLs including the host.
+//
+// If no handler is provided, the new group will inherit the handlers registered with the router.
+// Routes matching the host take precedence over the routes registered without host, and host patterns
+// are tried in the order they are first used.
+//
+//	api := r.Host("<tenant>.example.com")
+//	api.Get("/users", func(c *neo.Context) error {
+//	    return c.Write("users of " + c.Param("tenant"))
+//	})
+func (r *Router) Host(pattern string, handlers ...Handler) *RouteGroup {
+	var rg *RouteGroup
+	if len(handlers) == 0 {
+		rg = newRouteGroup("", r, combineHandlers(r.handlers, nil))
+		rg.names = combineNames(r.names, nil)
+	} else {
+		rg = newRouteGroup("", r, handlers)
+	}
+	rg.host = r.hostRoutes(pattern)
+	rg.options = r.options
+	return rg
+}
+
+// Host returns the host pattern of the route. An empty string is returned if the route matches any host.
+func (r *Route) Host() string {
+	if r.group.host == nil {
+		return ""
+	}
+	return r.group.host.pattern
+}
+
+// hostRoutes returns the hostRoutes with the given pattern, creating it if it does not exist yet.
+func (r *Router) hostRoutes(pattern string) *hostRoutes {
+	r.lock()
+	defer r.mu.Unlock()
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			return h
+		}
+	}
+	h := &hostRoutes{
+		pattern: pattern,
+		stores:  make(map[string]routeStore),
+	}
+	h.regex, h.pnames, h.indexes = compileHostPattern(pattern)
+	r.hosts = append(r.hosts, h)
+	return h
+}
+
+// compileHostPattern converts a host pattern into a regular expression capturing the host parameters.
+// The parameters are captured by named groups, so that the groups in their patterns do not shift
+// the submatches of the following parameters. The indexes of these submatches are returned as well.
+func compileHostPattern(pattern string) (*regexp.Regexp, []string, []int) {
+	var (
+		buf    strings.Builder
+		pnames []string
+	)
+	buf.WriteString("(?i)^")
+	start, end := -1, 0
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '<' && start < 0 {
+			start = i
+		} else if pattern[i] == '>' && start >= 0 {
+			buf.WriteString(regexp.QuoteMeta(pattern[end:start]))
+			name, expr := pattern[start+1:i], "[^.]+"
+			if j := strings.IndexByte(name, ':'); j >= 0 {
+				name, expr = name[:j], name[j+1:]
+			}
+			buf.WriteString("(?P<" + hostParamGroup(len(pnames)) + ">" + expr + ")")
+			pnames = append(pnames, name)
+			start, end = -1, i+1
+		}
+	}
+	buf.WriteString(regexp.QuoteMeta(pattern[end:]) + "$")
+	regex := regexp.MustCompile(buf.String())
+	var indexes []int
+	for i := range pnames {
+		indexes = append(indexes, regex.SubexpIndex(hostParamGroup(i)))
+	}
+	return regex, pnames, indexes
+}
+
+// hostParamGroup returns the name of the regular expression group capturing the host parameter at the given index.
+func hostParamGroup(i int) string {
+	return "host" + strconv.Itoa(i)
+}
+
+// findHost finds the handlers of the route matching the host, the method and the path.
+// The host parameter values are stored in pvalues after the path parameter values.
+func (r *Router) findHost(c *Context, method, host, path string, pvalues []string) ([]Handler, []string) {
+	host = stripHostPort(host)
+	for _, h := range r.hosts {
+		store := h.stores[method]
+		if store == nil {
+			continue
+		}
+		matches := h.regex.FindStringSubmatch(host)
+		if matches == nil {
+			continue
+		}
+		handlers, pnames := matchStore(c, store, path, pvalues)
+		if handlers == nil {
+			continue
+		}
+		if len(h.pnames) == 0 {
+			return handlers, pnames
+		}
+		names := make([]string, len(pnames), len(pnames)+len(h.pnames))
+		copy(names, pnames)
+		names = append(names, h.pnames...)
+		for i, index := range h.indexes {
+			pvalues[len(pnames)+i] = matches[index]
+		}
+		return handlers, names
+	}
+	return nil, nil
+}
+
+// stripHostPort removes the port, if any, from the given host.
+func stripHostPort(host string) string {
+	i := strings.LastIndexByte(host, ':')
+	if i < 0 || strings.IndexByte(host[i:], ']') >= 0 {
+		// no port, or an IPv6 address without port
+		return host
+	}
+	return host[:i]
+}
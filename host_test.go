@@ -0,0 +1,91 @@
+package neo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileHostPattern(t *testing.T) {
+	regex, pnames, indexes := compileHostPattern("<tenant>.example.com")
+	assert.Equal(t, `(?i)^(?P<host0>[^.]+)\.example\.com$`, regex.String())
+	assert.Equal(t, []string{"tenant"}, pnames)
+	assert.Equal(t, []int{1}, indexes)
+
+	regex, pnames, indexes = compileHostPattern(`<sub:[a-z]+-\d+>.<region>.example.com`)
+	assert.Equal(t, `(?i)^(?P<host0>[a-z]+-\d+)\.(?P<host1>[^.]+)\.example\.com$`, regex.String())
+	assert.Equal(t, []string{"sub", "region"}, pnames)
+	assert.Equal(t, []int{1, 2}, indexes)
+
+	// the groups in the parameter patterns are skipped
+	regex, pnames, indexes = compileHostPattern(`<sub:(a|b)(c)>.<region>.example.com`)
+	assert.Equal(t, []string{"sub", "region"}, pnames)
+	assert.Equal(t, []int{1, 4}, indexes)
+	assert.Equal(t, []string{"ac.eu.example.com", "ac", "a", "c", "eu"}, regex.FindStringSubmatch("ac.eu.example.com"))
+
+	regex, pnames, indexes = compileHostPattern("example.com")
+	assert.Equal(t, `(?i)^example\.com$`, regex.String())
+	assert.Nil(t, pnames)
+	assert.Nil(t, indexes)
+}
+
+func TestStripHostPort(t *testing.T) {
+	assert.Equal(t, "example.com", stripHostPort("example.com:8080"))
+	assert.Equal(t, "example.com", stripHostPort("example.com"))
+	assert.Equal(t, "[::1]", stripHostPort("[::1]:80"))
+	assert.Equal(t, "[::1]", stripHostPort("[::1]"))
+}
+
+func TestRouterHost(t *testing.T) {
+	router := New()
+	h := func(c *Context) error {
+		return c.Write(c.Param("tenant") + ":" + c.Param("id"))
+	}
+	router.Get("/users/<id>", func(c *Context) error {
+		return c.Write("default:" + c.Param("id"))
+	})
+	tenant := router.Host("<tenant>.example.com")
+	tenant.Get("/users/<id>", h).Name("tenant-user")
+	tenant.Group("/admin").Get("/users/<id>", h)
+	router.Host("admin.example.com").Get("/users/<id>", func(c *Context) error {
+		return c.Write("admin:" + c.Param("id"))
+	})
+	assert.Same(t, tenant.host, router.Host("<tenant>.example.com").host)
+	assert.Equal(t, "<tenant>.example.com", router.Route("tenant-user").Host())
+	assert.Equal(t, 2, router.maxParams)
+
+	tests := []struct {
+		host, path, body string
+		status           int
+	}{
+		{"acme.example.com", "/users/1", "acme:1", http.StatusOK},
+		{"ACME.Example.com:8080", "/users/2", "ACME:2", http.StatusOK},
+		{"acme.example.com", "/admin/users/3", "acme:3", http.StatusOK},
+		{"example.com", "/users/4", "default:4", http.StatusOK},
+		{"a.b.example.com", "/users/5", "default:5", http.StatusOK},
+		{"example.com", "/admin/users/3", "Not Found\n", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "http://"+test.host+test.path, nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.status, res.Code, test.host+test.path)
+		assert.Equal(t, test.body, res.Body.String(), test.host+test.path)
+	}
+
+	assert.Equal(t, "//acme.example.com/users/12", router.Route("tenant-user").URL("tenant", "acme", "id", 12))
+}
+
+func TestRouterHostGroups(t *testing.T) {
+	router := New()
+	router.Host("<env:(dev|prod)>.<region>.example.com").Get("/users/<id>", func(c *Context) error {
+		return c.Write(c.Param("env") + ":" + c.Param("region") + ":" + c.Param("id"))
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "http://prod.eu.example.com/users/1", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "prod:eu:1", res.Body.String())
+}
@@ -0,0 +1,55 @@
+// Command neo-routes renders the routes served by the debug endpoint of a running service
+// (see inspect.Handler) as a table, a tree or JSON.
+//
+//	neo-routes -format tree http://localhost:8080/debug/routes
+//
+// If the argument is "-", the JSON is read from the standard input instead.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/caeret/neo/inspect"
+)
+
+func main() {
+	format := flag.String("format", "table", `output format: "table", "tree" or "json"`)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: neo-routes [-format table|tree|json] <url|->")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *format, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "neo-routes:", err)
+		os.Exit(1)
+	}
+}
+
+func run(source, format string, out io.Writer) error {
+	var r io.Reader = os.Stdin
+	if source != "-" {
+		res, err := http.Get(source)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("%v: %v", source, res.Status)
+		}
+		r = res.Body
+	}
+	var routes []inspect.Route
+	if err := json.NewDecoder(r).Decode(&routes); err != nil {
+		return err
+	}
+	return inspect.Write(out, routes, format)
+}
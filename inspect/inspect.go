@@ -0,0 +1,231 @@
+// Package inspect renders the routes of a neo.Router for humans and tools.
+//
+// Routes collects the routes of a router, which can then be written as a table via WriteTable,
+// as a path tree via WriteTree, or served as JSON from a running service via Handler:
+//
+//	r.Get("/debug/routes", inspect.Handler(r))
+//
+// The neo-routes command under inspect/cmd fetches the JSON from such an endpoint and renders it.
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/caeret/neo"
+)
+
+// Route describes a route of a router.
+type Route struct {
+	Method     string   `json:"method"`
+	Host       string   `json:"host,omitempty"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name,omitempty"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	Source     string   `json:"source,omitempty"`
+}
+
+// Routes returns the descriptions of all routes of the router, sorted by host, path and method.
+// Handler and middleware names are the function names without the package path, such as "api.getUser".
+func Routes(router *neo.Router) []Route {
+	var routes []Route
+	for _, r := range router.Routes() {
+		route := Route{
+			Method:  r.Method(),
+			Host:    r.Host(),
+			Path:    r.Path(),
+			Name:    r.RouteName(),
+			Handler: shortName(r.Handler()),
+			Source:  r.Source(),
+		}
+		if names := r.HandlerNames(); len(names) > 1 {
+			for _, name := range names[:len(names)-1] {
+				route.Middleware = append(route.Middleware, shortName(name))
+			}
+		}
+		for _, tag := range r.Tags() {
+			route.Tags = append(route.Tags, fmt.Sprint(tag))
+		}
+		if v := r.Version(); v != nil {
+			route.Version = v.Name
+		}
+		routes = append(routes, route)
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return methodOrder(a.Method) < methodOrder(b.Method)
+	})
+	return routes
+}
+
+// WriteTable writes the routes as an aligned table with the columns METHOD, PATH, NAME, HANDLER, MIDDLEWARE and TAGS.
+// The paths of the routes matching a host are prefixed with "//host".
+func WriteTable(w io.Writer, routes []Route) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tNAME\tHANDLER\tMIDDLEWARE\tTAGS")
+	for _, r := range routes {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\n", r.Method, fullPath(r), dash(r.Name), dash(r.Handler),
+			dash(strings.Join(r.Middleware, " > ")), dash(strings.Join(r.Tags, ", ")))
+	}
+	return tw.Flush()
+}
+
+// WriteTree writes the routes as a tree of path segments. Each segment served by routes is followed by their methods.
+func WriteTree(w io.Writer, routes []Route) error {
+	root := &treeNode{}
+	for _, r := range routes {
+		n := root
+		if r.Host != "" {
+			n = n.child("//" + r.Host)
+		}
+		for _, segment := range splitPath(r.Path) {
+			n = n.child(segment)
+		}
+		n.methods = append(n.methods, r.Method)
+	}
+	var buf strings.Builder
+	for _, n := range root.children {
+		n.write(&buf, "", "")
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// WriteJSON writes the routes as a JSON array.
+func WriteJSON(w io.Writer, routes []Route) error {
+	if routes == nil {
+		routes = []Route{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(routes)
+}
+
+// Write writes the routes in the given format, which is "table", "tree" or "json".
+func Write(w io.Writer, routes []Route, format string) error {
+	switch format {
+	case "table", "":
+		return WriteTable(w, routes)
+	case "tree":
+		return WriteTree(w, routes)
+	case "json":
+		return WriteJSON(w, routes)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// Handler returns a handler serving the routes of the router as JSON. It is meant to be registered
+// under a debug path of a running service, preferably protected by authentication.
+// The routes are collected on each request so that routes changed at runtime are reflected.
+func Handler(router *neo.Router) neo.Handler {
+	return func(c *neo.Context) error {
+		c.Response.Header().Set("Content-Type", neo.MIME_JSON)
+		return WriteJSON(c.Response, Routes(router))
+	}
+}
+
+// treeNode is a path segment in the output of WriteTree.
+type treeNode struct {
+	segment  string
+	methods  []string
+	children []*treeNode
+}
+
+// child returns the child node with the given segment, creating it if it does not exist.
+func (n *treeNode) child(segment string) *treeNode {
+	for _, c := range n.children {
+		if c.segment == segment {
+			return c
+		}
+	}
+	c := &treeNode{segment: segment}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *treeNode) write(buf *strings.Builder, prefix, childPrefix string) {
+	buf.WriteString(prefix + n.segment)
+	if len(n.methods) > 0 {
+		buf.WriteString(" [" + strings.Join(n.methods, ", ") + "]")
+	}
+	buf.WriteString("\n")
+	for i, c := range n.children {
+		if i == len(n.children)-1 {
+			c.write(buf, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			c.write(buf, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// splitPath splits a route path into the root "/" followed by its segments. Slashes inside parameter
+// tokens, such as "<path:.*/.*>", do not split segments, and a trailing slash is kept in the last segment.
+func splitPath(path string) []string {
+	segments := []string{"/"}
+	start, depth := 1, 0
+	for i := 1; i < len(path); i++ {
+		switch path[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case '/':
+			if depth == 0 {
+				segments = append(segments, path[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(path) {
+		segments = append(segments, path[start:])
+	} else if len(segments) > 1 {
+		// keep the trailing slash so that "/users/" and "/users" are different nodes
+		segments[len(segments)-1] += "/"
+	}
+	return segments
+}
+
+func fullPath(r Route) string {
+	if r.Host != "" {
+		return "//" + r.Host + r.Path
+	}
+	return r.Path
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// methodOrder returns the sort order of an HTTP method, placing the common methods first.
+func methodOrder(method string) string {
+	for i, m := range []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"} {
+		if m == method {
+			return string(rune('0' + i))
+		}
+	}
+	return method
+}
+
+// shortName removes the package path from a function name, e.g. "github.com/org/app/api.getUser" becomes "api.getUser".
+func shortName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
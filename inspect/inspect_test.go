@@ -0,0 +1,109 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/caeret/neo"
+	"github.com/stretchr/testify/assert"
+)
+
+func auth(*neo.Context) error      { return nil }
+func listUsers(*neo.Context) error { return nil }
+func getUser(*neo.Context) error   { return nil }
+func health(*neo.Context) error    { return nil }
+
+func newRouter() *neo.Router {
+	router := neo.New()
+	router.Get("/health", health)
+	api := router.Group("/api", auth)
+	api.Get("/users", listUsers).Name("users").Tag("public")
+	api.To("GET,PUT", "/users/<id:\\d+>", getUser)
+	router.Host("<tenant>.example.com").Get("/", health)
+	return router
+}
+
+func TestRoutes(t *testing.T) {
+	routes := Routes(newRouter())
+	if !assert.Len(t, routes, 5) {
+		return
+	}
+	assert.Equal(t, Route{
+		Method:     "GET",
+		Path:       "/api/users",
+		Name:       "users",
+		Handler:    "inspect.listUsers",
+		Middleware: []string{"inspect.auth"},
+		Tags:       []string{"public"},
+		Source:     routes[0].Source,
+	}, routes[0])
+	assert.Contains(t, routes[0].Source, "inspect_test.go:24")
+	var paths []string
+	for _, r := range routes {
+		paths = append(paths, r.Method+" "+fullPath(r))
+	}
+	assert.Equal(t, []string{
+		"GET /api/users",
+		"GET /api/users/<id:\\d+>",
+		"PUT /api/users/<id:\\d+>",
+		"GET /health",
+		"GET //<tenant>.example.com/",
+	}, paths)
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteTable(&buf, Routes(newRouter())))
+	assert.Equal(t, `METHOD  PATH                     NAME   HANDLER            MIDDLEWARE    TAGS
+GET     /api/users               users  inspect.listUsers  inspect.auth  public
+GET     /api/users/<id:\d+>      -      inspect.getUser    inspect.auth  -
+PUT     /api/users/<id:\d+>      -      inspect.getUser    inspect.auth  -
+GET     /health                  -      inspect.health     -             -
+GET     //<tenant>.example.com/  -      inspect.health     -             -
+`, buf.String())
+}
+
+func TestWriteTree(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteTree(&buf, Routes(newRouter())))
+	assert.Equal(t, `/
+├── api
+│   └── users [GET]
+│       └── <id:\d+> [GET, PUT]
+└── health [GET]
+//<tenant>.example.com
+└── / [GET]
+`, buf.String())
+}
+
+func TestSplitPath(t *testing.T) {
+	assert.Equal(t, []string{"/"}, splitPath("/"))
+	assert.Equal(t, []string{"/", "users", "<id>"}, splitPath("/users/<id>"))
+	assert.Equal(t, []string{"/", "files", "<path:a/.*>"}, splitPath("/files/<path:a/.*>"))
+	assert.Equal(t, []string{"/", "users/"}, splitPath("/users/"))
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, Write(&buf, nil, "json"))
+	assert.Equal(t, "[]\n", buf.String())
+	assert.EqualError(t, Write(&buf, nil, "xml"), `unknown format "xml"`)
+}
+
+func TestHandler(t *testing.T) {
+	router := newRouter()
+	router.Get("/debug/routes", Handler(router))
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/debug/routes", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, neo.MIME_JSON, res.Header().Get("Content-Type"))
+	var routes []Route
+	assert.Nil(t, json.NewDecoder(strings.NewReader(res.Body.String())).Decode(&routes))
+	assert.Len(t, routes, 6)
+	assert.Equal(t, "/api/users", routes[0].Path)
+}
@@ -0,0 +1,158 @@
+package neo
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Matcher reports whether a request satisfies an additional condition of a route.
+// Matchers are attached to routes via Route.Match and are checked after the method and the path are matched.
+type Matcher func(*http.Request) bool
+
+// routeEntry is the data kept in a routeStore for a method and a path.
+// It holds all routes registered with the same method and path, in the order of registration.
+type routeEntry struct {
+	routes []*Route
+	ptypes []*paramType // the types of the path parameters, or nil if no parameter is typed
+}
+
+// handlers returns the handlers of the first route accepting the request.
+// Routes with matchers are tried before routes without matchers, so that a route without matchers
+// serves as the fallback of the routes with matchers. Among the accepting routes registered via
+// RouteGroup.Version, the one with the version selected for the request is used.
+// Nil is returned if no route accepts the request.
+// If the request is nil, matchers are ignored and the handlers of the first route are returned.
+func (e *routeEntry) handlers(req *http.Request) []Handler {
+	if req == nil {
+		return e.routes[0].handlers
+	}
+	var (
+		fallback  *Route
+		versioned []*Route
+	)
+	for _, route := range e.routes {
+		switch {
+		case !route.matches(req):
+		case route.group.version != nil:
+			versioned = append(versioned, route)
+		case len(route.matchers) > 0:
+			return route.handlers
+		case fallback == nil:
+			fallback = route
+		}
+	}
+	if len(versioned) > 0 {
+		if route := selectVersion(req, versioned); route != nil {
+			return route.handlers
+		}
+	}
+	if fallback != nil {
+		return fallback.handlers
+	}
+	return nil
+}
+
+// matchStore returns the handlers and the parameter names of the first route in the store matching the path
+// and accepting the request of the context, whose typed parameter values are parsed into the context.
+// If all routes of the entry matching the path reject the request, or a typed parameter value cannot be parsed,
+// the entries matching the path that were added later, such as "/users/<id>" after "/users/me", are tried
+// in turn. If the context is nil, the route matchers and the parameter types are ignored.
+func matchStore(c *Context, store routeStore, path string, pvalues []string) (handlers []Handler, pnames []string) {
+	var req *http.Request
+	if c != nil {
+		req = c.Request
+	}
+	order := 0
+	for {
+		var data interface{}
+		if data, pnames, order = store.GetAfter(path, pvalues, order); data == nil {
+			return nil, pnames
+		}
+		entry := data.(*routeEntry)
+		if handlers = entry.handlers(req); handlers != nil && (c == nil || c.parseParams(entry.ptypes)) {
+			return handlers, pnames
+		}
+	}
+}
+
+// Match adds matchers that a request must satisfy in addition to the method and the path of the route.
+// When several routes are registered with the same method and path, the first route whose matchers all accept
+// the request handles it. A route without matchers handles the requests rejected by all other routes.
+// If all these routes reject a request, the request falls through to the next route with another path pattern
+// matching the request path, such as "/users/<id>" for "/users/me", in the order the routes were added.
+// If no route accepts a request, the request is handled by the NotFound handlers.
+//
+//	r.Get("/search", searchV2).Match(neo.MatchHeader("Accept-Version", "2"))
+//	r.Get("/search", searchV1)
+func (r *Route) Match(matchers ...Matcher) *Route {
+	if len(r.routes) > 0 {
+		// this route is a composite one (a path with multiple methods)
+		for _, route := range r.routes {
+			route.Match(matchers...)
+		}
+		return r
+	}
+	router := r.group.router
+	router.lock()
+	r.matchers = append(r.matchers, matchers...)
+	router.mu.Unlock()
+	return r
+}
+
+// matches returns whether all matchers of the route accept the request.
+func (r *Route) matches(req *http.Request) bool {
+	for _, m := range r.matchers {
+		if !m(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchHeader returns a Matcher accepting requests with the named header.
+// If values are given, the header must have one of the values.
+func MatchHeader(name string, values ...string) Matcher {
+	return func(req *http.Request) bool {
+		return matchValues(req.Header.Values(name), values)
+	}
+}
+
+// MatchQuery returns a Matcher accepting requests with the named URL query parameter.
+// If values are given, the parameter must have one of the values.
+func MatchQuery(key string, values ...string) Matcher {
+	return func(req *http.Request) bool {
+		return matchValues(req.URL.Query()[key], values)
+	}
+}
+
+// MatchContentType returns a Matcher accepting requests whose Content-Type header (ignoring the parameters)
+// is one of the given MIME types.
+func MatchContentType(types ...string) Matcher {
+	return func(req *http.Request) bool {
+		t := getContentType(req)
+		for _, typ := range types {
+			if strings.EqualFold(t, typ) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchValues returns whether actual is not empty and, if expected is not empty, contains one of the expected values.
+func matchValues(actual, expected []string) bool {
+	if len(actual) == 0 {
+		return false
+	}
+	if len(expected) == 0 {
+		return true
+	}
+	for _, a := range actual {
+		for _, e := range expected {
+			if a == e {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package neo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteMatch(t *testing.T) {
+	router := New()
+	write := func(s string) Handler {
+		return func(c *Context) error {
+			return c.Write(s)
+		}
+	}
+	router.Get("/search", write("v2")).Match(MatchHeader("Accept-Version", "2", "2.0"))
+	router.Get("/search", write("any"))
+	router.Get("/search", write("debug")).Match(MatchQuery("debug"))
+	router.Get("/search", write("internal")).Match(func(req *http.Request) bool {
+		return strings.HasPrefix(req.RemoteAddr, "10.")
+	})
+	router.To("POST,PUT", "/items", write("json")).Match(MatchContentType(MIME_JSON))
+	router.Post("/items", write("form")).Match(MatchContentType(MIME_FORM, MIME_MULTIPART_FORM))
+	router.Get("/items", write("items v3")).Match(MatchHeader("Accept-Version", "3"))
+
+	tests := []struct {
+		method, url string
+		header      map[string]string
+		remoteAddr  string
+		status      int
+		body        string
+	}{
+		{"GET", "/search", map[string]string{"Accept-Version": "2"}, "", http.StatusOK, "v2"},
+		{"GET", "/search", map[string]string{"Accept-Version": "2.0"}, "", http.StatusOK, "v2"},
+		{"GET", "/search", map[string]string{"Accept-Version": "1"}, "", http.StatusOK, "any"},
+		{"GET", "/search", nil, "", http.StatusOK, "any"},
+		{"GET", "/search?debug", nil, "", http.StatusOK, "debug"},
+		{"GET", "/search", nil, "10.0.0.1:1234", http.StatusOK, "internal"},
+		{"POST", "/items", map[string]string{"Content-Type": "application/json; charset=utf-8"}, "", http.StatusOK, "json"},
+		{"PUT", "/items", map[string]string{"Content-Type": "application/json"}, "", http.StatusOK, "json"},
+		{"POST", "/items", map[string]string{"Content-Type": "multipart/form-data; boundary=x"}, "", http.StatusOK, "form"},
+		{"POST", "/items", map[string]string{"Content-Type": "text/plain"}, "", http.StatusNotFound, "Not Found\n"},
+		{"GET", "/items", map[string]string{"Accept-Version": "3"}, "", http.StatusOK, "items v3"},
+		{"GET", "/items", nil, "", http.StatusNotFound, "Not Found\n"},
+		{"DELETE", "/items", nil, "", http.StatusMethodNotAllowed, "Method Not Allowed\n"},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		for k, v := range test.header {
+			req.Header.Set(k, v)
+		}
+		req.RemoteAddr = test.remoteAddr
+		router.ServeHTTP(res, req)
+		tag := test.method + " " + test.url
+		assert.Equal(t, test.status, res.Code, tag)
+		assert.Equal(t, test.body, res.Body.String(), tag)
+	}
+
+	handlers, _ := router.Find("GET", "/search")
+	assert.Len(t, handlers, 1)
+	assert.Len(t, router.Routes(), 8)
+}
+
+func TestRouteMatchFallthrough(t *testing.T) {
+	router := New()
+	router.Get("/users/me", func(c *Context) error { return c.Write("me") }).Match(MatchHeader("Authorization", "token"))
+	router.Get("/users/<id>", func(c *Context) error { return c.Write("user " + c.Param("id")) })
+	router.Get("/users/<id>/posts", func(c *Context) error { return c.Write("posts") }).Match(MatchQuery("all"))
+	router.Get("/<path...>", func(c *Context) error { return c.Write("page " + c.Param("path")) })
+
+	tests := []struct {
+		url, auth, body string
+	}{
+		{"/users/me", "token", "me"},
+		{"/users/me", "", "user me"},
+		{"/users/12", "", "user 12"},
+		{"/users/12/posts?all", "", "posts"},
+		{"/users/12/posts", "", "page users/12/posts"},
+	}
+	for _, frozen := range []bool{false, true} {
+		if frozen {
+			router.Freeze()
+		}
+		for _, test := range tests {
+			res := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", test.url, nil)
+			if test.auth != "" {
+				req.Header.Set("Authorization", test.auth)
+			}
+			router.ServeHTTP(res, req)
+			assert.Equal(t, test.body, res.Body.String(), test.url)
+		}
+	}
+}
+
+func TestRouteMatchConcurrent(t *testing.T) {
+	router := New()
+	route := router.Get("/search", func(c *Context) error { return c.Write("search") })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			res := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/search", nil)
+			router.ServeHTTP(res, req)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		route.Match(func(*http.Request) bool { return true })
+	}
+	<-done
+
+	router.Freeze()
+	assert.Panics(t, func() { route.Match(MatchQuery("q")) })
+}
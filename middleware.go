@@ -0,0 +1,109 @@
+package neo
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// UseNamed registers a handler under the given name to the current route group.
+// Like the handlers registered via Use, it is shared by all routes added to the group and its subgroups
+// afterwards, and it can be skipped by its name via Skip.
+//
+//	r.UseNamed("auth", auth.JWT(key))
+//	r.Get("/health", health).Skip("auth")
+func (rg *RouteGroup) UseNamed(name string, handler Handler) {
+	rg.handlers = append(rg.handlers, handler)
+	rg.names = append(rg.names, name)
+}
+
+// UseNamed registers a named handler to the router and shares it with all routes.
+func (r *Router) UseNamed(name string, handler Handler) {
+	r.lock()
+	defer r.mu.Unlock()
+	r.RouteGroup.UseNamed(name, handler)
+	r.combineFallbackHandlers()
+}
+
+// Skip removes the handlers with the given names from the handlers inherited by the routes
+// and subgroups added to the group afterwards. See Route.HandlerNames for how handlers are named.
+//
+//	public := api.Group("/public").Skip("auth")
+func (rg *RouteGroup) Skip(names ...string) *RouteGroup {
+	rg.handlers, rg.names = skipHandlers(rg.handlers, rg.names, names)
+	return rg
+}
+
+// Skip removes the handlers with the given names, including the ones inherited from the group, from the route.
+// The handlers stay skipped when the route handlers are replaced via Replace.
+func (r *Route) Skip(names ...string) *Route {
+	if len(r.routes) > 0 {
+		// this route is a composite one (a path with multiple methods)
+		for _, route := range r.routes {
+			route.Skip(names...)
+		}
+		return r
+	}
+	router := r.group.router
+	router.lock()
+	r.skipped = append(r.skipped, names...)
+	r.handlers, r.names = skipHandlers(r.handlers, r.names, names)
+	router.mu.Unlock()
+	return r
+}
+
+// HandlerNames returns the names of the route handlers in the order they are called, starting with the ones
+// inherited from the group. A handler registered via UseNamed has the given name. Other handlers are named
+// after their functions, such as "github.com/caeret/neo/access.CustomLogger.func1".
+func (r *Route) HandlerNames() []string {
+	router := r.group.router
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	return r.names
+}
+
+// handlerNames returns the function names of the handlers.
+func handlerNames(handlers []Handler) []string {
+	names := make([]string, len(handlers))
+	for i, h := range handlers {
+		names[i] = runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	}
+	return names
+}
+
+// combineNames merges two lists of handler names into a new list.
+func combineNames(n1, n2 []string) []string {
+	nn := make([]string, len(n1)+len(n2))
+	copy(nn, n1)
+	copy(nn[len(n1):], n2)
+	return nn
+}
+
+// skipHandlers returns new lists of handlers and their names without the handlers with the skipped names.
+// A function name also matches without its package path, e.g. "access.CustomLogger.func1".
+func skipHandlers(handlers []Handler, names []string, skipped []string) ([]Handler, []string) {
+	if len(skipped) == 0 {
+		return handlers, names
+	}
+	hh, nn := make([]Handler, 0, len(handlers)), make([]string, 0, len(names))
+	for i, name := range names {
+		if !isSkipped(name, skipped) {
+			hh = append(hh, handlers[i])
+			nn = append(nn, name)
+		}
+	}
+	return hh, nn
+}
+
+func isSkipped(name string, skipped []string) bool {
+	short := name
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		short = name[i+1:]
+	}
+	for _, s := range skipped {
+		if s == name || s == short {
+			return true
+		}
+	}
+	return false
+}
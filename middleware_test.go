@@ -0,0 +1,64 @@
+package neo
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func namedHandler(*Context) error { return nil }
+
+func TestRouteHandlerNames(t *testing.T) {
+	var buf bytes.Buffer
+	router := New()
+	router.UseNamed("logger", newHandler("logger.", &buf))
+	router.Use(namedHandler)
+	api := router.Group("/api")
+	api.UseNamed("auth", newHandler("auth.", &buf))
+	route := api.Get("/users", newHandler("users.", &buf))
+
+	assert.Equal(t, []string{"logger", "github.com/caeret/neo.namedHandler", "auth", "github.com/caeret/neo.newHandler.func1"}, route.HandlerNames())
+	assert.Equal(t, []string{"logger", "github.com/caeret/neo.namedHandler", "auth"}, api.With(namedHandler).names[:3])
+
+	v1 := router.Version("v1")
+	assert.Equal(t, "version", v1.Get("/users").HandlerNames()[2])
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/unknown", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "logger.", buf.String())
+}
+
+func TestSkip(t *testing.T) {
+	var buf bytes.Buffer
+	router := New()
+	router.UseNamed("logger", newHandler("logger.", &buf))
+	router.Use(namedHandler)
+	api := router.Group("/api")
+	api.UseNamed("auth", newHandler("auth.", &buf))
+	api.Get("/users", newHandler("users.", &buf))
+	api.Get("/login", newHandler("login.", &buf)).Skip("auth", "neo.namedHandler")
+	api.Group("/public").Skip("auth").Get("/docs", newHandler("docs.", &buf))
+	route := api.To("GET,POST", "/health", newHandler("health.", &buf)).Skip("logger")
+	route.Replace(newHandler("replaced.", &buf))
+
+	tests := []struct {
+		method, path, chain string
+	}{
+		{"GET", "/api/users", "logger.auth.users."},
+		{"GET", "/api/login", "logger.login."},
+		{"GET", "/api/public/docs", "logger.docs."},
+		{"GET", "/api/health", "auth.replaced."},
+		{"POST", "/api/health", "auth.replaced."},
+	}
+	for _, test := range tests {
+		buf.Reset()
+		req, _ := http.NewRequest(test.method, test.path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, test.chain, buf.String(), test.method+" "+test.path)
+	}
+	assert.Equal(t, []string{"logger", "github.com/caeret/neo.newHandler.func1"}, router.Routes()[1].HandlerNames())
+}
@@ -0,0 +1,86 @@
+package neo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// originalPathKey is the request context key of the request path before a mount prefix is stripped.
+type originalPathKey struct{}
+
+// Mount adds routes with all methods listed in Methods, which serve the requests for the given path prefix and
+// any path under it via the given http.Handler, such as a third-party mux or net/http/pprof handlers.
+//
+// The prefix, including the prefix of the group and any parameter it contains, is stripped from the path of
+// the request given to the handler: "/debug/pprof/heap" is served as "/pprof/heap" by a handler mounted at
+// "/debug", while "/debug" itself is served as "/". Both URL.Path and URL.RawPath are set, and the original
+// request path can be obtained via OriginalPath.
+//
+//	r.Mount("/debug", http.DefaultServeMux)
+func (rg *RouteGroup) Mount(prefix string, h http.Handler) *Route {
+	prefix = strings.TrimSuffix(prefix, "/")
+	// the index of the path parameter matching the rest of the path. The host parameters follow the path parameters.
+	offset := len(paramToken.FindAllString(rg.prefix+prefix, -1))
+	hostParams := 0
+	if rg.host != nil {
+		hostParams = len(rg.host.pnames)
+	}
+	return rg.Any(prefix+"[/<:.*>]", func(c *Context) error {
+		rest := "/"
+		if len(c.pnames)-hostParams > offset {
+			rest += c.pvalues[offset]
+		}
+		req := c.Request
+		ctx := context.WithValue(req.Context(), originalPathKey{}, OriginalPath(req))
+		mounted := req.WithContext(ctx)
+		u := *req.URL
+		u.Path = mountedPath(req.URL.Path, rest, false)
+		u.RawPath = ""
+		if req.URL.RawPath != "" {
+			u.RawPath = mountedPath(req.URL.RawPath, rest, true)
+			if p, err := url.PathUnescape(u.RawPath); err != nil || p != u.Path {
+				u.RawPath = ""
+			}
+		}
+		mounted.URL = &u
+		h.ServeHTTP(c.Response, mounted)
+		return nil
+	})
+}
+
+// OriginalPath returns the URL path of a request before the prefix of the handler serving it was stripped
+// by RouteGroup.Mount. The URL path is returned if the request is not served by a mounted handler.
+func OriginalPath(req *http.Request) string {
+	if path, ok := req.Context().Value(originalPathKey{}).(string); ok {
+		return path
+	}
+	return req.URL.Path
+}
+
+// mountedPath returns the suffix of the request path, starting with a slash, which matches the rest of the path
+// following a mount prefix, ignoring trailing slashes removed by the router. If the path is escaped, the suffix
+// is compared after being unescaped. The rest is returned if no suffix matches it, such as for a cleaned path.
+func mountedPath(path, rest string, escaped bool) string {
+	want := strings.TrimRight(rest, "/")
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] != '/' {
+			continue
+		}
+		s := path[i:]
+		if escaped {
+			var err error
+			if s, err = url.PathUnescape(s); err != nil {
+				break
+			}
+		}
+		if t := strings.TrimRight(s, "/"); t == want {
+			return path[i:]
+		} else if len(t) > len(want) {
+			// longer suffixes cannot match
+			break
+		}
+	}
+	return rest
+}
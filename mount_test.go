@@ -0,0 +1,82 @@
+package neo
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteGroupMount(t *testing.T) {
+	router := New()
+	h := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(res, "%v %v %v %v", req.Method, req.URL.Path, req.URL.RawPath, OriginalPath(req))
+	})
+	router.Mount("/debug/", h)
+	router.Group("/users/<id>").Mount("/files", h)
+	router.Get("/debugger", func(c *Context) error { return c.Write("debugger") })
+
+	tests := []struct {
+		method, url, expected string
+	}{
+		{"GET", "/debug", "GET /  /debug"},
+		{"GET", "/debug/", "GET /  /debug/"},
+		{"POST", "/debug/pprof/heap?debug=1", "POST /pprof/heap  /debug/pprof/heap"},
+		{"GET", "/debug/pprof/", "GET /pprof/  /debug/pprof/"},
+		{"DELETE", "/debug/a%2Fb/c", "DELETE /a/b/c /a%2Fb/c /debug/a/b/c"},
+		{"GET", "/debugger", "debugger"},
+		{"PUT", "/users/12/files/a.txt", "PUT /a.txt  /users/12/files/a.txt"},
+		{"GET", "/users/12/files", "GET /  /users/12/files"},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.expected, res.Body.String(), test.url)
+	}
+
+	req, _ := http.NewRequest("GET", "/debug", nil)
+	assert.Equal(t, "/debug", OriginalPath(req))
+}
+
+func TestRouteGroupMountWithHost(t *testing.T) {
+	router := New()
+	router.Host("<tenant>.example.com").Group("/sites/<site>").Mount("/debug", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(res, req.URL.Path)
+	}))
+
+	tests := []struct {
+		url, expected string
+	}{
+		{"http://acme.example.com/sites/blog/debug/pprof", "/pprof"},
+		{"http://acme.example.com/sites/blog/debug", "/"},
+		{"http://acme.example.com/sites/blog/debug/", "/"},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", test.url, nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.expected, res.Body.String(), test.url)
+	}
+}
+
+func TestMountedPath(t *testing.T) {
+	tests := []struct {
+		path, rest string
+		escaped    bool
+		expected   string
+	}{
+		{"/debug", "/", false, "/"},
+		{"/debug/", "/", false, "/"},
+		{"/debug/pprof/", "/pprof", false, "/pprof/"},
+		{"/debug/pprof//", "/pprof/", false, "/pprof//"},
+		{"/debug/a%2Fb", "/a/b", true, "/a%2Fb"},
+		{"/debug/./pprof", "/pprof", false, "/pprof"},
+		{"/debug/other", "/pprof", false, "/pprof"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, mountedPath(test.path, test.rest, test.escaped), test.path)
+	}
+}
@@ -0,0 +1,176 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/caeret/neo"
+)
+
+// MIME types of the served documents.
+const (
+	JSON = neo.MIME_JSON
+	YAML = "application/yaml"
+)
+
+// Options specifies how the OpenAPI document is generated and served.
+type Options struct {
+	// Info describes the API. It is copied into the generated document as is.
+	Info Info
+	// Servers lists the servers hosting the API.
+	Servers []Server
+	// Path is the endpoint path of the document without file extension. Defaults to "/openapi".
+	// Register serves the document at Path+".json" and Path+".yaml".
+	Path string
+}
+
+// Handler returns a handler that serves the OpenAPI document describing the routes of the given router.
+// The document is generated when the handler is called for the first time, and generated again when
+// the routes of the router have been added or removed since then, so that routes registered after
+// creating the handler are included as well. A document failing to be encoded is not kept.
+//
+// The document is served in YAML if the request path ends with ".yaml" or ".yml", or if the "Accept"
+// header asks for YAML. Otherwise it is served in JSON.
+func Handler(router *neo.Router, opts Options) neo.Handler {
+	var (
+		mu       sync.Mutex
+		routes   []*neo.Route // the routes described by the generated document
+		jsonData []byte
+		yamlData []byte
+	)
+	return func(c *neo.Context) error {
+		mu.Lock()
+		if current := router.Routes(); jsonData == nil || !sameRoutes(routes, current) {
+			doc := Build(opts.Info, current)
+			doc.Servers = opts.Servers
+			j, err := MarshalJSON(doc)
+			var y []byte
+			if err == nil {
+				y, err = MarshalYAML(doc)
+			}
+			if err != nil {
+				mu.Unlock()
+				return err
+			}
+			routes, jsonData, yamlData = append([]*neo.Route(nil), current...), j, y
+		}
+		j, y := jsonData, yamlData
+		mu.Unlock()
+
+		if wantsYAML(c) {
+			c.Response.Header().Set("Content-Type", YAML)
+			_, err := c.Response.Write(y)
+			return err
+		}
+		c.Response.Header().Set("Content-Type", JSON)
+		_, err := c.Response.Write(j)
+		return err
+	}
+}
+
+// sameRoutes reports whether the route lists hold the same routes in the same order.
+func sameRoutes(a, b []*neo.Route) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Register adds the GET routes serving the OpenAPI document of the router in JSON and YAML.
+// The routes are excluded from the document itself.
+//
+// The routes registered before are linked to their operations in the JSON document via their metadata
+// (see neo.Route.Meta), which is returned by the automatic OPTIONS responses of the routes. The content
+// types declared via Accepts are added to the metadata as well, unless it lists accepted content types.
+//
+//	r := neo.New()
+//	openapi.Register(r, openapi.Options{
+//	    Info: openapi.Info{Title: "Users API", Version: "1.0.0"},
+//	    Path: "/docs/openapi",
+//	})
+func Register(router *neo.Router, opts Options) {
+	if opts.Path == "" {
+		opts.Path = "/openapi"
+	}
+	for _, route := range router.Routes() {
+		linkRoute(route, opts.Path+".json")
+	}
+	h := Handler(router, opts)
+	router.Get(opts.Path+".json", h).Tag(Ignore)
+	router.Get(opts.Path+".yaml", h).Tag(Ignore)
+}
+
+// linkRoute adds the link to the route operation in the document at the given URL to the route metadata.
+func linkRoute(route *neo.Route, url string) {
+	method := strings.ToLower(route.Method())
+	if method == "connect" || isIgnored(route) {
+		return
+	}
+	var meta neo.RouteMeta
+	if m := route.Metadata(); m != nil {
+		meta = *m
+	}
+	if meta.Link == "" {
+		path, _ := convertPath(route.Paths()[0], nil)
+		meta.Link = url + "#/paths/" + escapePointer(path) + "/" + method
+	}
+	if len(meta.Accept) == 0 {
+		for _, tag := range route.Tags() {
+			if t, ok := tag.(requestTag); ok {
+				meta.Accept = t.contentTypes
+			}
+		}
+	}
+	route.Meta(meta)
+}
+
+// escapePointer escapes a JSON pointer reference token (RFC 6901) for use in a URI fragment.
+func escapePointer(token string) string {
+	token = strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+	return strings.NewReplacer("{", "%7B", "}", "%7D").Replace(token)
+}
+
+// MarshalJSON encodes the document as indented JSON.
+func MarshalJSON(doc *Document) ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// MarshalYAML encodes the document as YAML.
+// The field order is the same as that of the JSON encoding.
+func MarshalYAML(doc *Document) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is a subset of YAML: decode it into a node tree to keep the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+// resetStyle switches the flow style nodes decoded from JSON to the block style.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+func wantsYAML(c *neo.Context) bool {
+	path := c.Request.URL.Path
+	if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+		return true
+	}
+	return strings.Contains(c.Request.Header.Get("Accept"), "yaml")
+}
@@ -0,0 +1,305 @@
+// Package openapi generates OpenAPI 3.1 documents from the routes registered with a neo.Router.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caeret/neo"
+)
+
+// Version is the OpenAPI specification version of the generated documents.
+const Version = "3.1.0"
+
+type (
+	// Document is the root object of an OpenAPI document.
+	Document struct {
+		OpenAPI    string              `json:"openapi"`
+		Info       Info                `json:"info"`
+		Servers    []Server            `json:"servers,omitempty"`
+		Paths      map[string]PathItem `json:"paths"`
+		Components *Components         `json:"components,omitempty"`
+	}
+
+	// Info provides metadata about the API.
+	Info struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Server represents a server hosting the API.
+	Server struct {
+		URL         string `json:"url"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// PathItem describes the operations available on a single path, keyed by the lower-cased HTTP method.
+	PathItem map[string]*Operation
+
+	// Operation describes a single API operation on a path.
+	Operation struct {
+		OperationID string               `json:"operationId,omitempty"`
+		Summary     string               `json:"summary,omitempty"`
+		Description string               `json:"description,omitempty"`
+		Tags        []string             `json:"tags,omitempty"`
+		Deprecated  bool                 `json:"deprecated,omitempty"`
+		Parameters  []Parameter          `json:"parameters,omitempty"`
+		RequestBody *RequestBody         `json:"requestBody,omitempty"`
+		Responses   map[string]*Response `json:"responses"`
+	}
+
+	// Parameter describes a single operation parameter.
+	Parameter struct {
+		Name     string  `json:"name"`
+		In       string  `json:"in"`
+		Required bool    `json:"required,omitempty"`
+		Schema   *Schema `json:"schema,omitempty"`
+	}
+
+	// RequestBody describes a single request body.
+	RequestBody struct {
+		Required bool                 `json:"required,omitempty"`
+		Content  map[string]MediaType `json:"content"`
+	}
+
+	// Response describes a single response from an API operation.
+	Response struct {
+		Description string               `json:"description"`
+		Content     map[string]MediaType `json:"content,omitempty"`
+	}
+
+	// MediaType provides the schema for a media type.
+	MediaType struct {
+		Schema *Schema `json:"schema,omitempty"`
+	}
+
+	// Components holds the reusable schemas referenced by the document.
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas,omitempty"`
+	}
+)
+
+// Meta carries the descriptive fields of an operation.
+// Attach it to a route via Route.Tag():
+//
+//	r.Get("/users", listUsers).Tag(openapi.Meta{Summary: "List users"})
+type Meta struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+}
+
+type (
+	requestTag struct {
+		value        interface{}
+		contentTypes []string
+	}
+
+	responseTag struct {
+		status      int
+		value       interface{}
+		description string
+	}
+
+	ignoreTag struct{}
+)
+
+// Ignore is a route tag that excludes the route from the generated document.
+var Ignore interface{} = ignoreTag{}
+
+// Accepts returns a route tag declaring the Go type of the request body.
+// The value is only used for its type. If no content type is given, "application/json" is assumed.
+//
+//	r.Post("/users", createUser).Tag(openapi.Accepts(User{}))
+func Accepts(value interface{}, contentTypes ...string) interface{} {
+	if len(contentTypes) == 0 {
+		contentTypes = []string{neo.MIME_JSON}
+	}
+	return requestTag{value, contentTypes}
+}
+
+// Returns returns a route tag declaring the Go type returned with the given status code.
+// The value is only used for its type and may be nil for responses without a body.
+// If the description is not given, http.StatusText() of the status is used.
+//
+//	r.Get("/users/<id>", getUser).Tag(openapi.Returns(http.StatusOK, User{}))
+func Returns(status int, value interface{}, description ...string) interface{} {
+	desc := http.StatusText(status)
+	if len(description) > 0 {
+		desc = description[0]
+	}
+	return responseTag{status, value, desc}
+}
+
+// Build generates an OpenAPI document describing the given routes.
+// The routes registered via neo.Router.Host are not described: a document maps each path and method
+// to a single operation, which cannot tell apart the routes of different hosts.
+func Build(info Info, routes []*neo.Route) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+	gen := newSchemaGenerator()
+
+	for _, route := range routes {
+		method := strings.ToLower(route.Method())
+		if method == "connect" || isIgnored(route) {
+			continue
+		}
+		// a route with optional segments is described by one path for each of its variants
+		for _, p := range route.Paths() {
+			path, params := convertPath(p, route.Router().ParamTypeRegex)
+			item := doc.Paths[path]
+			if item == nil {
+				item = PathItem{}
+				doc.Paths[path] = item
+			}
+			item[method] = buildOperation(gen, route, params)
+		}
+	}
+
+	if len(gen.schemas) > 0 {
+		doc.Components = &Components{Schemas: gen.schemas}
+	}
+	return doc
+}
+
+func buildOperation(gen *schemaGenerator, route *neo.Route, params []Parameter) *Operation {
+	op := &Operation{
+		OperationID: operationID(route),
+		Parameters:  params,
+		Responses:   map[string]*Response{},
+	}
+	for _, tag := range route.Tags() {
+		switch t := tag.(type) {
+		case Meta:
+			if t.OperationID != "" {
+				op.OperationID = t.OperationID
+			}
+			op.Summary = t.Summary
+			op.Description = t.Description
+			op.Tags = append(op.Tags, t.Tags...)
+			op.Deprecated = t.Deprecated
+		case string:
+			op.Tags = append(op.Tags, t)
+		case requestTag:
+			schema := gen.generate(reflect.TypeOf(t.value))
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{}}
+			for _, ct := range t.contentTypes {
+				op.RequestBody.Content[ct] = MediaType{Schema: schema}
+			}
+		case responseTag:
+			res := &Response{Description: t.description}
+			if t.value != nil {
+				res.Content = map[string]MediaType{
+					neo.MIME_JSON: {Schema: gen.generate(reflect.TypeOf(t.value))},
+				}
+			}
+			op.Responses[strconv.Itoa(t.status)] = res
+		}
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+	return op
+}
+
+// operationID derives an operation ID from the method and the short name of the route handler.
+// An empty string is returned for anonymous handlers.
+func operationID(route *neo.Route) string {
+	name := strings.TrimSuffix(route.Handler(), "-fm")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" || strings.HasPrefix(name, "func") {
+		return ""
+	}
+	return strings.ToLower(route.Method()) + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// isIgnored reports whether the route is excluded from the document: it is tagged with Ignore, or it is
+// registered via neo.Router.Host, as the operations of different hosts with the same path and method
+// would replace each other.
+func isIgnored(route *neo.Route) bool {
+	if route.Host() != "" {
+		return true
+	}
+	for _, tag := range route.Tags() {
+		if _, ok := tag.(ignoreTag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// convertPath converts a route path such as "/users/<id:\d+>" into an OpenAPI path template
+// such as "/users/{id}" together with the corresponding path parameters.
+// Unnamed parameters, including the trailing asterisk, are named "param1", "param2", and so on.
+// Named wildcards such as "<path...>" become string parameters. The parameter types other than the built-in ones
+// are expanded into their regular expressions via paramTypes, if not nil.
+func convertPath(path string, paramTypes func(name string) (string, bool)) (string, []Parameter) {
+	if strings.HasSuffix(path, "*") {
+		path = path[:len(path)-1] + "<:.*>"
+	}
+	var (
+		buf    strings.Builder
+		params []Parameter
+	)
+	start := -1
+	for i := 0; i < len(path); i++ {
+		if path[i] == '<' && start < 0 {
+			start = i
+		} else if path[i] == '>' && start >= 0 {
+			name, pattern := path[start+1:i], ""
+			if j := strings.IndexByte(name, ':'); j >= 0 {
+				name, pattern = name[:j], name[j+1:]
+			} else if strings.HasSuffix(name, "...") {
+				// a named wildcard
+				name, pattern = strings.TrimSuffix(name, "..."), ".*"
+			}
+			if name == "" {
+				name = "param" + strconv.Itoa(len(params)+1)
+			}
+			params = append(params, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   patternSchema(pattern, paramTypes),
+			})
+			buf.WriteString("{" + name + "}")
+			start = -1
+		} else if start < 0 {
+			buf.WriteByte(path[i])
+		}
+	}
+	return buf.String(), params
+}
+
+var integerPattern = regexp.MustCompile(`^(\\d|\[0-9\])[+*]$`)
+
+// patternSchema derives the schema of a path parameter from its regular expression or its parameter type.
+func patternSchema(pattern string, paramTypes func(name string) (string, bool)) *Schema {
+	switch {
+	case pattern == "" || pattern == ".*" || pattern == "slug":
+		return &Schema{Type: "string"}
+	case pattern == "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	case pattern == "date":
+		return &Schema{Type: "string", Format: "date"}
+	case pattern == "int" || integerPattern.MatchString(pattern):
+		return &Schema{Type: "integer"}
+	}
+	if paramTypes != nil {
+		if regex, ok := paramTypes(pattern); ok {
+			return patternSchema(regex, nil)
+		}
+	}
+	return &Schema{Type: "string", Pattern: "^" + pattern + "$"}
+}
@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caeret/neo"
+)
+
+type user struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Email   *string   `json:"email"`
+	Tags    []string  `json:"tags,omitempty"`
+	Created time.Time `json:"created"`
+	Friends []*user   `json:"friends,omitempty"`
+	secret  string
+}
+
+func listUsers(c *neo.Context) error { return nil }
+
+func TestConvertPath(t *testing.T) {
+	path, params := convertPath(`/users/<id:\d+>/<action>/*`, nil)
+	assert.Equal(t, "/users/{id}/{action}/{param3}", path)
+	if assert.Len(t, params, 3) {
+		assert.Equal(t, Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}}, params[0])
+		assert.Equal(t, &Schema{Type: "string"}, params[1].Schema)
+		assert.Equal(t, &Schema{Type: "string"}, params[2].Schema)
+	}
+
+	path, params = convertPath(`/posts/<slug:[a-z-]+>`, nil)
+	assert.Equal(t, "/posts/{slug}", path)
+	assert.Equal(t, &Schema{Type: "string", Pattern: "^[a-z-]+$"}, params[0].Schema)
+
+	path, params = convertPath(`/orders/<id:int>/<ref:uuid>/<day:date>`, nil)
+	assert.Equal(t, "/orders/{id}/{ref}/{day}", path)
+	assert.Equal(t, &Schema{Type: "integer"}, params[0].Schema)
+	assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, params[1].Schema)
+	assert.Equal(t, &Schema{Type: "string", Format: "date"}, params[2].Schema)
+
+	path, params = convertPath("/files/<path...>", nil)
+	assert.Equal(t, "/files/{path}", path)
+	assert.Equal(t, &Schema{Type: "string"}, params[0].Schema)
+
+	path, params = convertPath("/users", nil)
+	assert.Equal(t, "/users", path)
+	assert.Nil(t, params)
+}
+
+func TestBuild(t *testing.T) {
+	r := neo.New()
+	r.Get("/users", listUsers).Tag(Meta{Summary: "List users", Tags: []string{"users"}}).
+		Tag(Returns(http.StatusOK, []user{}))
+	r.Post("/users", listUsers).Tag(Accepts(user{})).Tag(Returns(http.StatusCreated, user{}))
+	r.Get(`/users/<id:\d+>`, func(c *neo.Context) error { return nil }).Tag("users")
+	r.Connect("/users", listUsers)
+	r.Get("/internal", listUsers).Tag(Ignore)
+	// host routes are not described, so that they do not replace the operations of other routes
+	r.Host("admin.example.com").Get("/users", listUsers).Tag(Meta{Summary: "List admin users"})
+	r.Host("admin.example.com").Get("/admins", listUsers)
+
+	doc := Build(Info{Title: "test", Version: "1.0"}, r.Routes())
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Len(t, doc.Paths, 2)
+
+	op := doc.Paths["/users"]["get"]
+	if assert.NotNil(t, op) {
+		assert.Equal(t, "getListUsers", op.OperationID)
+		assert.Equal(t, "List users", op.Summary)
+		assert.Equal(t, []string{"users"}, op.Tags)
+		assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/user"}}, op.Responses["200"].Content[JSON].Schema)
+	}
+	op = doc.Paths["/users"]["post"]
+	if assert.NotNil(t, op) {
+		assert.Equal(t, "postListUsers", op.OperationID)
+		assert.Equal(t, "Created", op.Responses["201"].Description)
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/user"}, op.RequestBody.Content[JSON].Schema)
+	}
+	assert.Nil(t, doc.Paths["/users"]["connect"])
+	op = doc.Paths["/users/{id}"]["get"]
+	if assert.NotNil(t, op) {
+		assert.Equal(t, "", op.OperationID)
+		assert.Equal(t, []string{"users"}, op.Tags)
+		assert.Equal(t, "OK", op.Responses["200"].Description)
+		assert.Equal(t, "id", op.Parameters[0].Name)
+	}
+
+	schema := doc.Components.Schemas["user"]
+	if assert.NotNil(t, schema) {
+		assert.Equal(t, []string{"id", "name", "created"}, schema.Required)
+		assert.Len(t, schema.Properties, 6)
+		assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, schema.Properties["created"])
+		assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/user"}}, schema.Properties["friends"])
+	}
+}
+
+func TestRegister(t *testing.T) {
+	r := neo.New()
+	r.Get("/users", listUsers)
+	Register(r, Options{Info: Info{Title: "test", Version: "1.0"}})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	r.ServeHTTP(res, req)
+	assert.Equal(t, JSON, res.Header().Get("Content-Type"))
+	var doc Document
+	if assert.Nil(t, json.Unmarshal(res.Body.Bytes(), &doc)) {
+		assert.Equal(t, "test", doc.Info.Title)
+		assert.Len(t, doc.Paths, 1)
+	}
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/openapi.yaml", nil)
+	r.ServeHTTP(res, req)
+	assert.Equal(t, YAML, res.Header().Get("Content-Type"))
+	assert.Contains(t, res.Body.String(), "openapi: 3.1.0\n")
+	assert.Contains(t, res.Body.String(), "\"200\":\n")
+
+	// the document is generated again after the routes change
+	r.ParamType("hex", `[0-9a-f]+`, nil)
+	r.Get("/colors/<rgb:hex>", listUsers)
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/openapi.json", nil)
+	r.ServeHTTP(res, req)
+	doc = Document{}
+	if assert.Nil(t, json.Unmarshal(res.Body.Bytes(), &doc)) && assert.Len(t, doc.Paths, 2) {
+		params := doc.Paths["/colors/{rgb}"]["get"].Parameters
+		assert.Equal(t, &Schema{Type: "string", Pattern: "^[0-9a-f]+$"}, params[0].Schema)
+	}
+}
+
+func TestRegisterLinks(t *testing.T) {
+	r := neo.New()
+	r.Get("/users", listUsers)
+	r.Post("/users", listUsers).Tag(Accepts(user{}, "application/xml"))
+	r.Put("/users/<id>", listUsers).Meta(neo.RouteMeta{Auth: []string{"Bearer"}})
+	r.Get("/internal", listUsers).Tag(Ignore)
+	Register(r, Options{Info: Info{Title: "test", Version: "1.0"}})
+
+	assert.Equal(t, &neo.RouteMeta{Link: "/openapi.json#/paths/~1users/get"}, r.Routes()[0].Metadata())
+	assert.Equal(t, &neo.RouteMeta{
+		Accept: []string{"application/xml"},
+		Link:   "/openapi.json#/paths/~1users/post",
+	}, r.Routes()[1].Metadata())
+	assert.Equal(t, &neo.RouteMeta{
+		Auth: []string{"Bearer"},
+		Link: "/openapi.json#/paths/~1users~1%7Bid%7D/put",
+	}, r.Routes()[2].Metadata())
+	assert.Nil(t, r.Routes()[3].Metadata())
+}
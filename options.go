@@ -0,0 +1,164 @@
+package neo
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// RouteMeta is the machine-readable metadata of a route, returned by OptionsResponder for the OPTIONS
+// requests of the route path.
+type RouteMeta struct {
+	// Accept lists the content types accepted in the request body, such as "application/json".
+	Accept []string `json:"accept,omitempty"`
+	// Auth lists the accepted authentication schemes, such as "Bearer" or "Basic".
+	Auth []string `json:"auth,omitempty"`
+	// Link is the URL of the route documentation, such as the route operation in an OpenAPI document.
+	Link string `json:"link,omitempty"`
+}
+
+// OptionsResponse is the body written by OptionsResponder when some routes of the request path have metadata.
+type OptionsResponse struct {
+	// Allow lists the allowed methods, like the Allow header.
+	Allow []string `json:"allow"`
+	// Methods maps the methods to the metadata of their routes. Routes without metadata are omitted.
+	Methods map[string]*RouteMeta `json:"methods,omitempty"`
+}
+
+// Meta sets the metadata of the route returned by OptionsResponder.
+func (r *Route) Meta(meta RouteMeta) *Route {
+	if len(r.routes) > 0 {
+		// this route is a composite one (a path with multiple methods)
+		for _, route := range r.routes {
+			route.Meta(meta)
+		}
+		return r
+	}
+	router := r.group.router
+	router.lock()
+	r.meta = &meta
+	router.mu.Unlock()
+	return r
+}
+
+// Metadata returns the metadata of the route set via Meta. Nil is returned if the route has no metadata.
+func (r *Route) Metadata() *RouteMeta {
+	router := r.group.router
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	return r.meta
+}
+
+// OptionsHandler specifies the handlers responding to the OPTIONS requests of the paths of the group routes,
+// when the router responds to such requests automatically (see Router.HandleOptions). The handlers are looked up
+// when a request is served, so they apply to the routes added to the group before and after the call, while
+// the subgroups created afterwards inherit them. A path shared by the routes of several groups is handled
+// by the group of the route added first.
+// The handlers registered to the router via Use are invoked first, while the other group handlers are not,
+// so that preflight requests are not rejected by authentication handlers. By default, OptionsResponder is used.
+//
+//	api := r.Group("/api")
+//	api.OptionsHandler(cors.Handler(cors.AllowAll), neo.OptionsResponder)
+func (rg *RouteGroup) OptionsHandler(handlers ...Handler) *RouteGroup {
+	rg.router.lock()
+	defer rg.router.mu.Unlock()
+	rg.options = handlers
+	return rg
+}
+
+// optionsHandlers returns the handlers responding to the OPTIONS requests of the paths of the group routes.
+// The caller must hold the read lock of the router unless the router is frozen.
+func (rg *RouteGroup) optionsHandlers() []Handler {
+	if rg.options == nil {
+		return combineHandlers(rg.router.handlers, []Handler{OptionsResponder})
+	}
+	return combineHandlers(rg.router.handlers, rg.options)
+}
+
+// OptionsResponder responds to an OPTIONS request with the Allow header listing the methods allowed for
+// the request path. If some routes of the path have metadata (see Route.Meta), an OptionsResponse is written
+// as JSON. Otherwise, the response has no body.
+func OptionsResponder(c *Context) error {
+	allow := c.allowed()
+	if allow == nil {
+		return nil
+	}
+	c.Response.Header().Set("Allow", allow.header)
+	meta := c.router.routeMetadata(c.Request.Host, c.routePath(), allow.methods)
+	if len(meta) == 0 {
+		c.Response.WriteHeader(http.StatusOK)
+		return nil
+	}
+	data, err := json.Marshal(OptionsResponse{
+		Allow:   strings.Split(allow.header, ", "),
+		Methods: meta,
+	})
+	if err != nil {
+		return err
+	}
+	c.Response.Header().Set("Content-Type", MIME_JSON)
+	_, err = c.Response.Write(data)
+	return err
+}
+
+// AllowedMethods returns the methods allowed for the request path, in the format of the Allow header,
+// such as "GET, HEAD, OPTIONS". An empty string is returned if no route matches the request path.
+func (c *Context) AllowedMethods() string {
+	if allow := c.allowed(); allow != nil {
+		return allow.header
+	}
+	return ""
+}
+
+// allowed returns the allowEntry of the request path, computing it if the router has not done so.
+func (c *Context) allowed() *allowEntry {
+	if c.allow == nil && c.router != nil && c.Request != nil {
+		c.allow = c.router.findAllowed(c.Request.Host, c.routePath())
+	}
+	return c.allow
+}
+
+// routeMetadata returns the metadata of the routes matching the host and the path with the given methods.
+func (r *Router) routeMetadata(host, path string, methods map[string]bool) map[string]*RouteMeta {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ms := make([]string, 0, len(methods))
+	for method := range methods {
+		ms = append(ms, method)
+	}
+	sort.Strings(ms)
+	pvalues := make([]string, r.maxParams)
+	meta := map[string]*RouteMeta{}
+	for _, method := range ms {
+		data, _ := r.findStore(host, method, path, pvalues)
+		if data == nil {
+			continue
+		}
+		for _, route := range data.(*routeEntry).routes {
+			if route.meta != nil {
+				meta[method] = route.meta
+				break
+			}
+		}
+	}
+	return meta
+}
+
+// findStore returns the store data matching the host, the method and the path, regardless of the route matchers.
+func (r *Router) findStore(host, method, path string, pvalues []string) (interface{}, []string) {
+	if len(r.hosts) > 0 && host != "" {
+		host = stripHostPort(host)
+		for _, h := range r.hosts {
+			if s := h.stores[method]; s != nil && h.regex.MatchString(host) {
+				if data, pnames := s.Get(path, pvalues); data != nil {
+					return data, pnames
+				}
+			}
+		}
+	}
+	if s := r.stores[method]; s != nil {
+		return s.Get(path, pvalues)
+	}
+	return nil, nil
+}
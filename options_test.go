@@ -0,0 +1,146 @@
+package neo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterHandleOptions(t *testing.T) {
+	router := New()
+	router.Get("/users", func(c *Context) error { return c.Write("users") })
+	router.Post("/users", func(c *Context) error { return nil }).Meta(RouteMeta{
+		Accept: []string{MIME_JSON},
+		Auth:   []string{"Bearer"},
+		Link:   "/docs#create-user",
+	})
+	router.Get("/items/<id>", func(c *Context) error { return nil })
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("OPTIONS", "/items/1", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS", res.Header().Get("Allow"))
+	assert.Equal(t, "", res.Body.String())
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", res.Header().Get("Allow"))
+	assert.Equal(t, MIME_JSON, res.Header().Get("Content-Type"))
+	var body OptionsResponse
+	if assert.Nil(t, json.Unmarshal(res.Body.Bytes(), &body)) {
+		assert.Equal(t, []string{"GET", "HEAD", "OPTIONS", "POST"}, body.Allow)
+		assert.Equal(t, map[string]*RouteMeta{
+			"POST": {Accept: []string{MIME_JSON}, Auth: []string{"Bearer"}, Link: "/docs#create-user"},
+		}, body.Methods)
+	}
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/unknown", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	// an explicit OPTIONS route takes precedence
+	router.Options("/users", func(c *Context) error { return c.Write("explicit") })
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "explicit", res.Body.String())
+
+	// without HandleOptions, OPTIONS requests are handled like other disallowed methods
+	router.HandleOptions = false
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/items/1", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS", res.Header().Get("Allow"))
+	assert.Equal(t, "", res.Body.String())
+}
+
+func TestRouteGroupOptionsHandler(t *testing.T) {
+	router := New()
+	var calls string
+	router.Use(func(c *Context) error {
+		calls += "router,"
+		return nil
+	})
+	api := router.Group("/api", func(c *Context) error {
+		calls += "auth,"
+		return NewHTTPError(http.StatusUnauthorized)
+	})
+	api.OptionsHandler(func(c *Context) error {
+		calls += "options:" + c.AllowedMethods()
+		return OptionsResponder(c)
+	})
+	api.Put("/users/<id>", func(c *Context) error { return nil })
+	v1 := api.Group("/v1")
+	v1.Delete("/users/<id>", func(c *Context) error { return nil })
+	router.Get("/home", func(c *Context) error { return nil })
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("OPTIONS", "/api/users/1", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "OPTIONS, PUT", res.Header().Get("Allow"))
+	assert.Equal(t, "router,options:OPTIONS, PUT", calls)
+
+	calls = ""
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/api/v1/users/1", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "DELETE, OPTIONS", res.Header().Get("Allow"))
+	assert.Equal(t, "router,options:DELETE, OPTIONS", calls)
+
+	calls = ""
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/home", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "GET, HEAD, OPTIONS", res.Header().Get("Allow"))
+	assert.Equal(t, "router,", calls)
+}
+
+func TestContextAllowedMethods(t *testing.T) {
+	router := New()
+	var allowed string
+	router.Get("/users", func(c *Context) error {
+		allowed = c.AllowedMethods()
+		return nil
+	})
+	router.Patch("/users", func(c *Context) error { return nil })
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "GET, HEAD, OPTIONS, PATCH", allowed)
+
+	c := NewContext(res, req)
+	assert.Equal(t, "", c.AllowedMethods())
+}
+
+func TestRouteGroupOptionsHandlerConcurrent(t *testing.T) {
+	router := New()
+	api := router.Group("/api")
+	api.Get("/users", func(c *Context) error { return nil })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			res := httptest.NewRecorder()
+			req, _ := http.NewRequest("OPTIONS", "/api/users", nil)
+			router.ServeHTTP(res, req)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		api.OptionsHandler(OptionsResponder)
+	}
+	<-done
+
+	router.Freeze()
+	assert.Panics(t, func() { api.OptionsHandler(OptionsResponder) })
+}
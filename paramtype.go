@@ -0,0 +1,162 @@
+package neo
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParamParser converts the value of a typed route parameter into its typed value.
+type ParamParser func(value string) (interface{}, error)
+
+// paramType is a named route parameter type registered via Router.ParamType.
+type paramType struct {
+	regex  string
+	parser ParamParser
+}
+
+// builtinParamTypes returns the parameter types available in all routers.
+func builtinParamTypes() map[string]*paramType {
+	return map[string]*paramType{
+		"int": {`-?[0-9]+`, func(value string) (interface{}, error) {
+			return strconv.Atoi(value)
+		}},
+		"uuid": {`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`, func(value string) (interface{}, error) {
+			return strings.ToLower(value), nil
+		}},
+		"date": {`[0-9]{4}-[0-9]{2}-[0-9]{2}`, func(value string) (interface{}, error) {
+			return time.Parse("2006-01-02", value)
+		}},
+		"slug": {`[a-z0-9]+(?:-[a-z0-9]+)*`, nil},
+	}
+}
+
+// ParamType registers a named route parameter type, so that route paths can declare parameters such as
+// "<id:int>" instead of "<id:-?[0-9]+>". The regex determines the values matched by the parameter, and
+// the optional parser converts the matched value into the typed value returned by Context.ParamValue.
+// If the parser fails, the route does not match the request, which is then matched against the next routes.
+//
+// The following types are built in:
+//
+//   - int: an integer, parsed as int.
+//   - uuid: a UUID, parsed as its lower case string.
+//   - date: a date in the format of "2006-01-02", parsed as time.Time.
+//   - slug: lower case words separated by hyphens, such as "hello-world", kept as string.
+//
+// Parameter types should be registered before the routes using them are added.
+//
+//	r.ParamType("hex", `[0-9a-f]+`, func(v string) (interface{}, error) {
+//	    return strconv.ParseUint(v, 16, 64)
+//	})
+//	r.Get("/colors/<rgb:hex>", ...)
+func (r *Router) ParamType(name, regex string, parser ParamParser) {
+	regexp.MustCompile(regex)
+	r.lock()
+	defer r.mu.Unlock()
+	r.paramTypes[name] = &paramType{regex, parser}
+}
+
+// ParamTypeRegex returns the regular expression of the named parameter type registered via ParamType,
+// including the built-in types. False is returned if no such type is registered.
+func (r *Router) ParamTypeRegex(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if pt := r.paramTypes[name]; pt != nil {
+		return pt.regex, true
+	}
+	return "", false
+}
+
+// expandParamTypes replaces the parameter types in the route path with their regular expressions.
+// It also returns the types of the parameters in the path, or nil if no parameter is typed.
+func (r *Router) expandParamTypes(path string) (string, []*paramType) {
+	var (
+		buf    strings.Builder
+		ptypes []*paramType
+		typed  bool
+	)
+	last := 0
+	for _, m := range paramToken.FindAllStringSubmatchIndex(path, -1) {
+		var pt *paramType
+		if m[4] >= 0 {
+			pt = r.paramTypes[path[m[4]:m[5]]]
+		}
+		ptypes = append(ptypes, pt)
+		if pt == nil {
+			continue
+		}
+		typed = true
+		buf.WriteString(path[last:m[4]])
+		buf.WriteString(pt.regex)
+		last = m[5]
+	}
+	if !typed {
+		return path, nil
+	}
+	buf.WriteString(path[last:])
+	return buf.String(), ptypes
+}
+
+// parseParams converts the values of the typed parameters. It returns false if a value cannot be parsed.
+// The values matched in an escaped path are unescaped before being parsed.
+func (c *Context) parseParams(ptypes []*paramType) bool {
+	if cap(c.ptyped) < len(ptypes) {
+		c.ptyped = make([]interface{}, len(ptypes))
+	}
+	c.ptyped = c.ptyped[:len(ptypes)]
+	for i, pt := range ptypes {
+		c.ptyped[i] = nil
+		if pt == nil || pt.parser == nil {
+			continue
+		}
+		value := c.pvalues[i]
+		if c.router != nil && c.router.UseEscapedPath {
+			value, _ = url.QueryUnescape(value)
+		}
+		v, err := pt.parser(value)
+		if err != nil {
+			return false
+		}
+		c.ptyped[i] = v
+	}
+	return true
+}
+
+// ParamValue returns the typed value of the named parameter, as converted by the parser of its type
+// (see Router.ParamType) when the route is matched. The string value is returned if the parameter
+// has no type or its type has no parser. Nil is returned if the named parameter cannot be found.
+func (c *Context) ParamValue(name string) interface{} {
+	for i, n := range c.pnames {
+		if n == name {
+			if i < len(c.ptyped) && c.ptyped[i] != nil {
+				return c.ptyped[i]
+			}
+			return c.pvalues[i]
+		}
+	}
+	return nil
+}
+
+// ParamInt returns the named parameter as an int. For a parameter of the "int" type, the value parsed
+// when the route is matched is returned. Otherwise, the string value is converted, and 0 is returned
+// if the parameter cannot be found or is not an integer.
+func (c *Context) ParamInt(name string) int {
+	switch v := c.ParamValue(name).(type) {
+	case int:
+		return v
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
+	}
+	return 0
+}
+
+// ParamTime returns the named parameter as a time.Time. For a parameter of the "date" type, the value parsed
+// when the route is matched is returned. The zero time is returned if the parameter cannot be found or
+// is not a time.
+func (c *Context) ParamTime(name string) time.Time {
+	t, _ := c.ParamValue(name).(time.Time)
+	return t
+}
@@ -0,0 +1,103 @@
+package neo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandParamTypes(t *testing.T) {
+	router := New()
+	path, ptypes := router.expandParamTypes("/users/<id:int>/<name>/<day:date>")
+	assert.Equal(t, `/users/<id:-?[0-9]+>/<name>/<day:[0-9]{4}-[0-9]{2}-[0-9]{2}>`, path)
+	if assert.Len(t, ptypes, 3) {
+		assert.Same(t, router.paramTypes["int"], ptypes[0])
+		assert.Nil(t, ptypes[1])
+		assert.Same(t, router.paramTypes["date"], ptypes[2])
+	}
+
+	path, ptypes = router.expandParamTypes(`/users/<id:\d+>/<name>`)
+	assert.Equal(t, `/users/<id:\d+>/<name>`, path)
+	assert.Nil(t, ptypes)
+}
+
+func TestRouterParamType(t *testing.T) {
+	router := New()
+	router.ParamType("hex", `[0-9a-f]+`, func(v string) (interface{}, error) {
+		return strconv.ParseUint(v, 16, 64)
+	})
+	router.Get("/users/<id:int>", func(c *Context) error {
+		assert.Equal(t, 12, c.ParamValue("id"))
+		return c.Write(c.ParamInt("id") + 1)
+	})
+	router.Get("/posts/<slug:slug>", func(c *Context) error {
+		return c.Write(c.ParamValue("slug"))
+	})
+	router.Get("/events/<day:date>", func(c *Context) error {
+		return c.Write(c.ParamTime("day").Weekday())
+	})
+	router.Get("/objects/<ref:uuid>", func(c *Context) error {
+		return c.Write(c.ParamValue("ref"))
+	})
+	router.Host("<tenant>.example.com").Get("/colors/<rgb:hex>", func(c *Context) error {
+		return c.Write(c.Param("tenant") + ":" + strconv.FormatUint(c.ParamValue("rgb").(uint64), 10))
+	})
+
+	tests := []struct {
+		url, body string
+		status    int
+	}{
+		{"/users/12", "13", http.StatusOK},
+		{"/users/abc", "Not Found\n", http.StatusNotFound},
+		{"/users/99999999999999999999999", "Not Found\n", http.StatusNotFound},
+		{"/posts/hello-world", "hello-world", http.StatusOK},
+		{"/posts/Hello", "Not Found\n", http.StatusNotFound},
+		{"/events/2026-10-17", "Saturday", http.StatusOK},
+		{"/events/2026-13-45", "Not Found\n", http.StatusNotFound},
+		{"/objects/0E984725-C51C-4BF4-9960-E1C80E27ABA0", "0e984725-c51c-4bf4-9960-e1c80e27aba0", http.StatusOK},
+		{"http://acme.example.com/colors/ff", "acme:255", http.StatusOK},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", test.url, nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.status, res.Code, test.url)
+		assert.Equal(t, test.body, res.Body.String(), test.url)
+	}
+
+	// a value that cannot be parsed falls through to the next route matching the path
+	router.Get("/users/<name>", func(c *Context) error { return c.Write("name " + c.Param("name")) })
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/99999999999999999999999", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "name 99999999999999999999999", res.Body.String())
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/12", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "13", res.Body.String())
+
+	regex, ok := router.ParamTypeRegex("hex")
+	assert.True(t, ok)
+	assert.Equal(t, `[0-9a-f]+`, regex)
+	regex, ok = router.ParamTypeRegex("int")
+	assert.True(t, ok)
+	assert.Equal(t, `-?[0-9]+`, regex)
+	_, ok = router.ParamTypeRegex("unknown")
+	assert.False(t, ok)
+}
+
+func TestContextParamValue(t *testing.T) {
+	c := NewContext(nil, nil)
+	c.SetParam("id", "42")
+	c.SetParam("name", "abc")
+	assert.Equal(t, "42", c.ParamValue("id"))
+	assert.Equal(t, 42, c.ParamInt("id"))
+	assert.Equal(t, 0, c.ParamInt("name"))
+	assert.Equal(t, 0, c.ParamInt("missing"))
+	assert.Nil(t, c.ParamValue("missing"))
+	assert.Equal(t, time.Time{}, c.ParamTime("id"))
+}
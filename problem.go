@@ -0,0 +1,329 @@
+package neo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MIME types of problem details responses as defined in RFC 9457.
+const (
+	MIME_PROBLEM_JSON = "application/problem+json"
+	MIME_PROBLEM_XML  = "application/problem+xml"
+)
+
+// problemNamespace is the XML namespace of problem details documents.
+const problemNamespace = "urn:ietf:rfc:7807"
+
+// ProblemDetails is an HTTPError carrying the problem details members defined in RFC 9457.
+// It is written as "application/problem+json" or "application/problem+xml" depending on
+// the "Accept" header of the request.
+type ProblemDetails struct {
+	// Type is a URI reference identifying the problem type. It defaults to "about:blank".
+	Type string
+	// Title is a short summary of the problem type.
+	Title string
+	// Status is the HTTP status code.
+	Status int
+	// Detail is an explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is a URI reference identifying this occurrence of the problem.
+	Instance string
+	// Extensions holds the extension members, which are written alongside the standard members.
+	Extensions map[string]interface{}
+}
+
+// NewProblem creates a ProblemDetails with the given status code and optional detail.
+// The title is set as http.StatusText() of the status code.
+func NewProblem(status int, detail ...string) *ProblemDetails {
+	p := &ProblemDetails{
+		Status: status,
+		Title:  http.StatusText(status),
+	}
+	if len(detail) > 0 {
+		p.Detail = detail[0]
+	}
+	return p
+}
+
+// ProblemFrom converts the given error into a ProblemDetails.
+//
+// A ProblemDetails found in the error chain is returned as is. A *ValidationError becomes a problem whose
+// "errors" extension member lists the field violations. Any other HTTPError, including the binding failures
+// reported by Context.Read and Typed, keeps its status code and uses its message as the detail.
+// All other errors become an http.StatusInternalServerError problem without detail so that internal
+// error messages are not exposed to clients.
+func ProblemFrom(err error) *ProblemDetails {
+	var (
+		p  *ProblemDetails
+		ve *ValidationError
+		he HTTPError
+	)
+	switch {
+	case errors.As(err, &p):
+		return p
+	case errors.As(err, &ve):
+		p = NewProblem(ve.StatusCode(), "The request data is invalid.")
+		p.Extensions = map[string]interface{}{"errors": ve.Errors}
+		return p
+	case errors.As(err, &he):
+		p = NewProblem(he.StatusCode())
+		if msg := he.Error(); msg != p.Title {
+			p.Detail = msg
+		}
+		if e, ok := he.(*httpError); ok {
+			if e.Code != "" {
+				p.With("code", e.Code)
+			}
+			if e.Details != nil {
+				p.With("details", e.Details)
+			}
+		}
+		return p
+	}
+	return NewProblem(http.StatusInternalServerError)
+}
+
+// Error returns the detail of the problem, or the title if the detail is empty.
+func (p *ProblemDetails) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// StatusCode returns the HTTP status code.
+func (p *ProblemDetails) StatusCode() int {
+	return p.Status
+}
+
+// With sets an extension member and returns the problem itself.
+func (p *ProblemDetails) With(name string, value interface{}) *ProblemDetails {
+	if p.Extensions == nil {
+		p.Extensions = map[string]interface{}{}
+	}
+	p.Extensions[name] = value
+	return p
+}
+
+// members returns the non-empty members of the problem, followed by the extension members sorted by name.
+func (p *ProblemDetails) members() (names []string, values []interface{}) {
+	add := func(name string, value interface{}) {
+		names = append(names, name)
+		values = append(values, value)
+	}
+	if p.Type != "" {
+		add("type", p.Type)
+	} else {
+		add("type", "about:blank")
+	}
+	if p.Title != "" {
+		add("title", p.Title)
+	}
+	if p.Status != 0 {
+		add("status", p.Status)
+	}
+	if p.Detail != "" {
+		add("detail", p.Detail)
+	}
+	if p.Instance != "" {
+		add("instance", p.Instance)
+	}
+	keys := make([]string, 0, len(p.Extensions))
+	for key := range p.Extensions {
+		switch key {
+		case "type", "title", "status", "detail", "instance":
+			// extension members cannot override the standard members
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add(key, p.Extensions[key])
+	}
+	return
+}
+
+// MarshalJSON encodes the problem as a JSON object with the extension members inlined.
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	names, values := p.members()
+	buf := []byte{'{'}
+	for i, name := range names {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf = append(strconv.AppendQuote(buf, name), ':')
+		buf = append(buf, value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// MarshalXML encodes the problem as a "problem" element in the RFC 9457 namespace.
+// A map extension member is encoded as an element with a child element per entry, sorted by key, and
+// each item of a slice extension member is encoded as an element named after the member.
+func (p *ProblemDetails) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Space: problemNamespace, Local: "problem"}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	names, values := p.members()
+	for i, name := range names {
+		if err := encodeXMLMember(e, name, reflect.ValueOf(values[i])); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// encodeXMLMember encodes a problem member as an element with the given name, including the maps,
+// which encoding/xml does not support.
+func encodeXMLMember(e *xml.Encoder, name string, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	switch v.Kind() {
+	case reflect.Map:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			if err := encodeXMLEntry(e, fmt.Sprint(key.Interface()), v.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeXMLMember(e, name, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Invalid:
+		return nil
+	}
+	return e.EncodeElement(v.Interface(), start)
+}
+
+// encodeXMLEntry encodes a map entry as an element named after the key, or as an "entry" element
+// with a "key" attribute if the key is not a valid XML name.
+func encodeXMLEntry(e *xml.Encoder, key string, v reflect.Value) error {
+	if isXMLName(key) {
+		return encodeXMLMember(e, key, v)
+	}
+	start := xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := encodeXMLMember(e, "value", v); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// isXMLName reports whether the string is a valid XML element name made of ASCII characters.
+func isXMLName(s string) bool {
+	if s == "" || strings.HasPrefix(strings.ToLower(s), "xml") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '_':
+		case '0' <= c && c <= '9', c == '-', c == '.':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WriteProblem writes the problem to the response with its status code.
+// The problem is written in XML if the request prefers XML over JSON according to
+// its "Accept" header. Otherwise, it is written in JSON. If the problem cannot be encoded in XML,
+// it is written in JSON instead. If it cannot be encoded at all, the status code is written without
+// a body and the encoding error is returned.
+func WriteProblem(c *Context, p *ProblemDetails) error {
+	var (
+		data []byte
+		err  error
+	)
+	contentType := negotiateProblemType(c.Request)
+	if contentType == MIME_PROBLEM_XML {
+		if data, err = xml.Marshal(p); err != nil {
+			contentType = MIME_PROBLEM_JSON
+		}
+	}
+	if contentType == MIME_PROBLEM_JSON {
+		data, err = json.Marshal(p)
+	}
+	if err != nil {
+		c.Response.WriteHeader(p.StatusCode())
+		return err
+	}
+	c.Response.Header().Set("Content-Type", contentType)
+	c.Response.WriteHeader(p.StatusCode())
+	_, err = c.Response.Write(data)
+	return err
+}
+
+// negotiateProblemType returns the problem details MIME type preferred by the request.
+func negotiateProblemType(req *http.Request) string {
+	jsonQ, xmlQ := -1.0, -1.0
+	for _, accept := range req.Header.Values("Accept") {
+		for _, r := range strings.Split(accept, ",") {
+			params := strings.Split(r, ";")
+			mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+			q := 1.0
+			for _, param := range params[1:] {
+				if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+					if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+						q = v
+					}
+				}
+			}
+			switch {
+			case strings.HasSuffix(mediaType, "/xml") || strings.HasSuffix(mediaType, "+xml"):
+				if q > xmlQ {
+					xmlQ = q
+				}
+			case strings.HasSuffix(mediaType, "/json") || strings.HasSuffix(mediaType, "+json"):
+				if q > jsonQ {
+					jsonQ = q
+				}
+			}
+		}
+	}
+	if xmlQ > 0 && xmlQ > jsonQ {
+		return MIME_PROBLEM_XML
+	}
+	return MIME_PROBLEM_JSON
+}
@@ -0,0 +1,107 @@
+package neo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemDetails(t *testing.T) {
+	p := NewProblem(http.StatusConflict, "the user exists").With("id", 12)
+	p.Type = "https://example.com/problems/conflict"
+	p.Instance = "/users/12"
+	assert.Equal(t, http.StatusConflict, p.StatusCode())
+	assert.Equal(t, "the user exists", p.Error())
+	assert.Equal(t, "Bad Request", NewProblem(http.StatusBadRequest).Error())
+
+	data, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"https://example.com/problems/conflict","title":"Conflict","status":409,"detail":"the user exists","instance":"/users/12","id":12}`, string(data))
+
+	data, err = xml.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `<problem xmlns="urn:ietf:rfc:7807"><type>https://example.com/problems/conflict</type><title>Conflict</title><status>409</status><detail>the user exists</detail><instance>/users/12</instance><id>12</id></problem>`, string(data))
+
+	p = &ProblemDetails{Status: http.StatusBadRequest, Extensions: map[string]interface{}{"status": 1}}
+	data, _ = json.Marshal(p)
+	assert.Equal(t, `{"type":"about:blank","status":400}`, string(data))
+}
+
+func TestProblemDetailsXMLExtensions(t *testing.T) {
+	p := NewProblem(http.StatusConflict).
+		With("details", map[string]interface{}{"name": "taken", "ids": []int{1, 2}, "1st": true}).
+		With("errors", []FieldError{{"name", "required", "is required"}})
+	data, err := xml.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Conflict</title><status>409</status>`+
+		`<details><entry key="1st"><value>true</value></entry><ids>1</ids><ids>2</ids><name>taken</name></details>`+
+		`<errors><field>name</field><rule>required</rule><message>is required</message></errors></problem>`, string(data))
+}
+
+func TestWriteProblemFallback(t *testing.T) {
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users", nil)
+	req.Header.Set("Accept", "application/xml")
+	c := NewContext(res, req)
+	// a func cannot be encoded in XML, but is skipped by the JSON encoding of a struct field
+	p := NewProblem(http.StatusConflict).With("handler", struct {
+		F func() `json:"-"`
+	}{})
+	assert.Nil(t, WriteProblem(c, p))
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Equal(t, MIME_PROBLEM_JSON, res.Header().Get("Content-Type"))
+
+	res = httptest.NewRecorder()
+	c = NewContext(res, req)
+	assert.NotNil(t, WriteProblem(c, NewProblem(http.StatusConflict).With("ch", make(chan int))))
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Equal(t, "", res.Body.String())
+}
+
+func TestProblemFrom(t *testing.T) {
+	p := NewProblem(http.StatusTeapot)
+	assert.Same(t, p, ProblemFrom(fmt.Errorf("wrapped: %w", p)))
+
+	p = ProblemFrom(&ValidationError{Errors: []FieldError{{"name", "required", "is required"}}})
+	assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+	data, _ := json.Marshal(p)
+	assert.Equal(t, `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"The request data is invalid.","errors":[{"field":"name","rule":"required","message":"is required"}]}`, string(data))
+
+	p = ProblemFrom(NewHTTPError(http.StatusBadRequest, "invalid id"))
+	assert.Equal(t, &ProblemDetails{Status: http.StatusBadRequest, Title: "Bad Request", Detail: "invalid id"}, p)
+	p = ProblemFrom(NewHTTPError(http.StatusNotFound))
+	assert.Equal(t, &ProblemDetails{Status: http.StatusNotFound, Title: "Not Found"}, p)
+	p = ProblemFrom(errors.New("db password leaked"))
+	assert.Equal(t, &ProblemDetails{Status: http.StatusInternalServerError, Title: "Internal Server Error"}, p)
+}
+
+func TestRouterProblem(t *testing.T) {
+	router := New()
+	router.Get("/users", func(c *Context) error {
+		return NewProblem(http.StatusForbidden)
+	})
+	tests := []struct {
+		accept, contentType string
+	}{
+		{"", MIME_PROBLEM_JSON},
+		{"application/json", MIME_PROBLEM_JSON},
+		{"application/xml", MIME_PROBLEM_XML},
+		{"application/json;q=0.5, text/xml", MIME_PROBLEM_XML},
+		{"application/xml;q=0.5, application/problem+json", MIME_PROBLEM_JSON},
+		{"text/html", MIME_PROBLEM_JSON},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users", nil)
+		req.Header.Set("Accept", test.accept)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code, test.accept)
+		assert.Equal(t, test.contentType, res.Header().Get("Content-Type"), test.accept)
+	}
+}
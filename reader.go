@@ -4,6 +4,7 @@ import (
 	"encoding"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -63,16 +64,100 @@ func (r *XMLDataReader) Read(req *http.Request, data interface{}) error {
 // FormDataReader reads the query parameters and request body as form data.
 type FormDataReader struct{}
 
+// Read populates data with the query parameters and the form values of the request body.
+// If data is a pointer to a struct, the fields tagged with `header:"Name"` and `cookie:"name"` are populated
+// with the request headers and cookies as well.
 func (r *FormDataReader) Read(req *http.Request, data interface{}) error {
 	// Do not check return result. Otherwise GET request will cause problem.
 	req.ParseMultipartForm(32 << 20)
-	return ReadFormData(req.Form, data)
+	if err := ReadFormData(req.Form, data); err != nil {
+		return err
+	}
+	return readHeaderCookieData(req, data)
+}
+
+// readHeaderCookieData populates the struct pointed to by data with the request headers and cookies.
+func readHeaderCookieData(req *http.Request, data interface{}) error {
+	if err := ReadHeaderData(req.Header, data); err != nil {
+		return err
+	}
+	return ReadCookieData(req.Cookies(), data)
+}
+
+// Struct tags specifying the names of the values used to populate struct fields.
+const (
+	formTag   = "form"
+	queryTag  = "query"
+	paramTag  = "param"
+	headerTag = "header"
+	cookieTag = "cookie"
+)
+
+// valueSource provides the values used to populate struct fields.
+type valueSource struct {
+	tag      string // the struct tag specifying the value name of a field
+	implicit bool   // whether fields without the tag are populated using their field names
+	kind     string // the kind of the values used in error messages. Errors are returned as is if empty.
+	lookup   func(name string) ([]string, bool)
+}
+
+// fieldError annotates the error occurred when populating the field with the named value.
+func (src valueSource) fieldError(name string, err error) error {
+	if src.kind == "" {
+		return err
+	}
+	return fmt.Errorf("invalid %v %q: %w", src.kind, name, err)
 }
 
-const formTag = "form"
+// formSource returns a valueSource that reads form values by the "form" tag or field names.
+func formSource(form map[string][]string) valueSource {
+	return valueSource{
+		tag:      formTag,
+		implicit: true,
+		lookup: func(name string) ([]string, bool) {
+			value, ok := form[name]
+			return value, ok
+		},
+	}
+}
 
 // ReadFormData populates the data variable with the data from the given form values.
 func ReadFormData(form map[string][]string, data interface{}) error {
+	return readData(formSource(form), data)
+}
+
+// ReadHeaderData populates the data variable with the given request headers.
+// Only the fields tagged with `header:"Name"` are populated. The header names are case-insensitive.
+func ReadHeaderData(header http.Header, data interface{}) error {
+	return readData(valueSource{
+		tag:  headerTag,
+		kind: "header",
+		lookup: func(name string) ([]string, bool) {
+			value, ok := header[http.CanonicalHeaderKey(name)]
+			return value, ok
+		},
+	}, data)
+}
+
+// ReadCookieData populates the data variable with the given request cookies.
+// Only the fields tagged with `cookie:"name"` are populated.
+func ReadCookieData(cookies []*http.Cookie, data interface{}) error {
+	return readData(valueSource{
+		tag:  cookieTag,
+		kind: "cookie",
+		lookup: func(name string) (value []string, ok bool) {
+			for _, cookie := range cookies {
+				if cookie.Name == name {
+					value = append(value, cookie.Value)
+				}
+			}
+			return value, len(value) > 0
+		},
+	}, data)
+}
+
+// readData populates the struct pointed to by data with the values from the given source.
+func readData(src valueSource, data interface{}) error {
 	rv := reflect.ValueOf(data)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return errors.New("data must be a pointer")
@@ -82,16 +167,22 @@ func ReadFormData(form map[string][]string, data interface{}) error {
 		return errors.New("data must be a pointer to a struct")
 	}
 
-	return readForm(form, "", rv)
+	return readValues(src, "", rv)
 }
 
-func readForm(form map[string][]string, prefix string, rv reflect.Value) error {
+// isStructPointer returns whether data is a non-nil pointer to a struct.
+func isStructPointer(data interface{}) bool {
+	rv := reflect.ValueOf(data)
+	return rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct
+}
+
+func readValues(src valueSource, prefix string, rv reflect.Value) error {
 	rv = indirect(rv)
 	rt := rv.Type()
 	n := rt.NumField()
 	for i := 0; i < n; i++ {
 		field := rt.Field(i)
-		tag := field.Tag.Get(formTag)
+		tag := field.Tag.Get(src.tag)
 
 		// only handle anonymous or exported fields
 		if !field.Anonymous && field.PkgPath != "" || tag == "-" {
@@ -104,40 +195,49 @@ func readForm(form map[string][]string, prefix string, rv reflect.Value) error {
 		}
 
 		name := tag
-		if name == "" && !field.Anonymous {
+		if name == "" && !field.Anonymous && src.implicit {
 			name = field.Name
 		}
 		if name != "" && prefix != "" {
 			name = prefix + "." + name
 		}
 
-		// check if type implements a known type, like encoding.TextUnmarshaler
-		if ok, err := readFormFieldKnownType(form, name, rv.Field(i)); err != nil {
-			return err
-		} else if ok {
-			continue
+		if name != "" {
+			// check if type implements a known type, like encoding.TextUnmarshaler
+			if ok, err := readFieldKnownType(src, name, rv.Field(i)); err != nil {
+				return src.fieldError(name, err)
+			} else if ok {
+				continue
+			}
 		}
 
 		if ft.Kind() != reflect.Struct {
-			if err := readFormField(form, name, rv.Field(i)); err != nil {
-				return err
+			if name == "" {
+				continue
+			}
+			if err := readField(src, name, rv.Field(i)); err != nil {
+				return src.fieldError(name, err)
 			}
 			continue
 		}
 
 		if name == "" {
+			if !field.Anonymous {
+				// untagged struct fields are only traversed by implicit sources
+				continue
+			}
 			name = prefix
 		}
-		if err := readForm(form, name, rv.Field(i)); err != nil {
+		if err := readValues(src, name, rv.Field(i)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func readFormFieldKnownType(form map[string][]string, name string, rv reflect.Value) (bool, error) {
-	value, ok := form[name]
-	if !ok {
+func readFieldKnownType(src valueSource, name string, rv reflect.Value) (bool, error) {
+	value, ok := src.lookup(name)
+	if !ok || len(value) == 0 {
 		return false, nil
 	}
 	rv = indirect(rv)
@@ -152,9 +252,9 @@ func readFormFieldKnownType(form map[string][]string, name string, rv reflect.Va
 	return false, nil
 }
 
-func readFormField(form map[string][]string, name string, rv reflect.Value) error {
-	value, ok := form[name]
-	if !ok {
+func readField(src valueSource, name string, rv reflect.Value) error {
+	value, ok := src.lookup(name)
+	if !ok || len(value) == 0 {
 		return nil
 	}
 	rv = indirect(rv)
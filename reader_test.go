@@ -126,3 +126,105 @@ func TestRead(t *testing.T) {
 		t.Errorf("read fail: %s", v.Foo)
 	}
 }
+
+func TestReadHeaderData(t *testing.T) {
+	var a struct {
+		Tenant  string  `header:"x-tenant"`
+		Keys    []int   `header:"X-Key"`
+		Trace   *string `header:"X-Trace"`
+		TU      TU      `header:"X-TU"`
+		Missing *int    `header:"X-Missing"`
+		Name    string
+		Items   []string `form:"items"`
+	}
+	header := http.Header{}
+	header.Set("X-Tenant", "acme")
+	header.Add("X-Key", "1")
+	header.Add("X-Key", "2")
+	header.Set("X-Trace", "abc")
+	header.Set("X-TU", "value")
+	header.Set("Name", "ignored")
+	assert.Nil(t, ReadHeaderData(header, &a))
+	assert.Equal(t, "acme", a.Tenant)
+	assert.Equal(t, []int{1, 2}, a.Keys)
+	assert.Equal(t, "abc", *a.Trace)
+	assert.Equal(t, "TU_value", a.TU.UValue)
+	assert.Nil(t, a.Missing)
+	assert.Equal(t, "", a.Name)
+
+	header.Set("X-Key", "abc")
+	err := ReadHeaderData(header, &a)
+	assert.Equal(t, `invalid header "X-Key": strconv.ParseInt: parsing "abc": invalid syntax`, err.Error())
+}
+
+func TestReadCookieData(t *testing.T) {
+	var a struct {
+		Session string   `cookie:"session"`
+		Prefs   []string `cookie:"pref"`
+		Count   *int     `cookie:"count"`
+	}
+	cookies := []*http.Cookie{
+		{Name: "session", Value: "s1"},
+		{Name: "pref", Value: "a"},
+		{Name: "pref", Value: "b"},
+		{Name: "count", Value: "3"},
+	}
+	assert.Nil(t, ReadCookieData(cookies, &a))
+	assert.Equal(t, "s1", a.Session)
+	assert.Equal(t, []string{"a", "b"}, a.Prefs)
+	assert.Equal(t, 3, *a.Count)
+}
+
+func TestFormDataReaderHeaderCookie(t *testing.T) {
+	var a struct {
+		Name    string `form:"name"`
+		Tenant  string `header:"X-Tenant"`
+		Session string `cookie:"session"`
+	}
+	req, _ := http.NewRequest("GET", "/users?name=abc", nil)
+	req.Header.Set("X-Tenant", "acme")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	assert.Nil(t, NewContext(nil, req).Read(&a))
+	assert.Equal(t, "abc", a.Name)
+	assert.Equal(t, "acme", a.Tenant)
+	assert.Equal(t, "s1", a.Session)
+
+	// the form data reader binds headers and cookies when used directly
+	var b struct {
+		Tenant  string   `header:"X-Tenant"`
+		Session string   `cookie:"session"`
+		Keys    []string `header:"X-Key"`
+	}
+	req.Header.Add("X-Key", "k1")
+	req.Header.Add("X-Key", "k2")
+	assert.Nil(t, DefaultFormDataReader.Read(req, &b))
+	assert.Equal(t, "acme", b.Tenant)
+	assert.Equal(t, "s1", b.Session)
+
+	// Context.Read binds them once
+	b.Keys = nil
+	assert.Nil(t, NewContext(nil, req).Read(&b))
+	assert.Equal(t, []string{"k1", "k2"}, b.Keys)
+}
+
+func TestContextReadErrors(t *testing.T) {
+	var a struct {
+		Name string `json:"name"`
+		Key  int    `header:"X-Key"`
+	}
+	req, _ := http.NewRequest("POST", "/users", strings.NewReader(`{"name":`))
+	req.Header.Set("Content-Type", MIME_JSON)
+	err := NewContext(nil, req).Read(&a)
+	if assert.Implements(t, (*HTTPError)(nil), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(HTTPError).StatusCode())
+	}
+
+	req, _ = http.NewRequest("POST", "/users", strings.NewReader(`{"name":"abc"}`))
+	req.Header.Set("Content-Type", MIME_JSON)
+	req.Header.Set("X-Key", "abc")
+	err = NewContext(nil, req).Read(&a)
+	if assert.Implements(t, (*HTTPError)(nil), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(HTTPError).StatusCode())
+		assert.Equal(t, `invalid header "X-Key": strconv.ParseInt: parsing "abc": invalid syntax`, err.Error())
+	}
+}
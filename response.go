@@ -0,0 +1,154 @@
+package neo
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// responseWriter wraps the http.ResponseWriter of a request to record whether the response has been started.
+// It is embedded in Context so that wrapping the response does not allocate.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	written bool
+	discard bool // whether to discard the response body, when a GET route serves a HEAD request
+}
+
+func (w *responseWriter) reset(res http.ResponseWriter) {
+	w.ResponseWriter = res
+	w.status = 0
+	w.written = false
+	w.discard = false
+}
+
+// WriteHeader sends the HTTP response header with the given status code.
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.written {
+		w.status = status
+		w.written = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes the data to the connection as part of the HTTP response.
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if !w.written {
+		w.status = http.StatusOK
+		w.written = true
+		if w.discard {
+			w.ResponseWriter.WriteHeader(http.StatusOK)
+		}
+	}
+	if w.discard {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// ReadFrom reads data from r and writes it to the response, using the io.ReaderFrom
+// implementation of the underlying writer if available.
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok && !w.discard {
+		if !w.written {
+			w.status = http.StatusOK
+			w.written = true
+		}
+		return rf.ReadFrom(r)
+	}
+	return io.Copy(struct{ io.Writer }{w}, r)
+}
+
+// Unwrap returns the underlying http.ResponseWriter. It is used by http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// wrapper returns the writer wrapping the response, which implements http.Flusher, http.Hijacker and
+// http.Pusher only if the underlying writer does. The wrappers hold a single pointer, so that they are
+// stored in an interface without allocating.
+func (w *responseWriter) wrapper() http.ResponseWriter {
+	_, f := w.ResponseWriter.(http.Flusher)
+	_, h := w.ResponseWriter.(http.Hijacker)
+	_, p := w.ResponseWriter.(http.Pusher)
+	switch {
+	case f && h && p:
+		return flushHijackPushWriter{w}
+	case f && h:
+		return flushHijackWriter{w}
+	case f && p:
+		return flushPushWriter{w}
+	case h && p:
+		return hijackPushWriter{w}
+	case f:
+		return flushWriter{w}
+	case h:
+		return hijackWriter{w}
+	case p:
+		return pushWriter{w}
+	}
+	return w
+}
+
+// flush sends any buffered data to the client. The underlying writer must implement http.Flusher.
+func (w *responseWriter) flush() {
+	if !w.written {
+		w.status = http.StatusOK
+		w.written = true
+	}
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijack lets the caller take over the connection. The underlying writer must implement http.Hijacker.
+func (w *responseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		w.written = true
+	}
+	return conn, rw, err
+}
+
+// push initiates an HTTP/2 server push. The underlying writer must implement http.Pusher.
+func (w *responseWriter) push(target string, opts *http.PushOptions) error {
+	return w.ResponseWriter.(http.Pusher).Push(target, opts)
+}
+
+type flushWriter struct{ *responseWriter }
+
+func (w flushWriter) Flush() { w.flush() }
+
+type hijackWriter struct{ *responseWriter }
+
+func (w hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+type pushWriter struct{ *responseWriter }
+
+func (w pushWriter) Push(target string, opts *http.PushOptions) error { return w.push(target, opts) }
+
+type flushHijackWriter struct{ *responseWriter }
+
+func (w flushHijackWriter) Flush()                                       { w.flush() }
+func (w flushHijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+type flushPushWriter struct{ *responseWriter }
+
+func (w flushPushWriter) Flush() { w.flush() }
+func (w flushPushWriter) Push(target string, opts *http.PushOptions) error {
+	return w.push(target, opts)
+}
+
+type hijackPushWriter struct{ *responseWriter }
+
+func (w hijackPushWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+func (w hijackPushWriter) Push(target string, opts *http.PushOptions) error {
+	return w.push(target, opts)
+}
+
+type flushHijackPushWriter struct{ *responseWriter }
+
+func (w flushHijackPushWriter) Flush()                                       { w.flush() }
+func (w flushHijackPushWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+func (w flushHijackPushWriter) Push(target string, opts *http.PushOptions) error {
+	return w.push(target, opts)
+}
@@ -0,0 +1,214 @@
+package neo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// URLOptions specifies the parts of a URL built via Route.BuildURL other than its path.
+type URLOptions struct {
+	// Query is encoded as the query string of the URL, with the keys sorted.
+	Query url.Values
+	// Fragment is appended to the URL after a "#".
+	Fragment string
+	// Scheme makes the URL absolute, such as "https://example.com/users". It requires a host.
+	Scheme string
+	// Host is the host of the URL of a route matching any host. The host of a route registered
+	// via Router.Host is built from its host pattern instead, and this field is ignored.
+	Host string
+}
+
+// BuildURL builds the URL of the route from the given parameter values. Unlike URL, it returns an error
+// if a parameter of the route is not provided a value, if a value does not match the pattern or the type
+// of its parameter, or if a value is provided for an unknown parameter. Values are escaped as path segments,
+// and the value of a named wildcard, such as "<path...>", keeps its slashes.
+//
+// An optional segment is included if all of its parameters are provided values, and left out if none is.
+// If the route is registered via Router.Host, the host parameters must be provided as well.
+//
+//	r.Get("/users/<id:int>[/<tab>]", ...).Name("user")
+//	u, err := r.Route("user").BuildURL(map[string]interface{}{"id": 12}, neo.URLOptions{
+//	    Query: url.Values{"sort": {"name"}},
+//	})
+//	// u is "/users/12?sort=name"
+func (r *Route) BuildURL(params map[string]interface{}, options ...URLOptions) (string, error) {
+	var opts URLOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	router := r.group.router
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+
+	path, err := r.buildPath(params)
+	if err != nil {
+		return "", err
+	}
+
+	host := opts.Host
+	if pattern := r.Host(); pattern != "" {
+		if host, err = fillTokens(pattern, params, "[^.]+", nil); err != nil {
+			return "", err
+		}
+	}
+	var buf strings.Builder
+	if opts.Scheme != "" {
+		if host == "" {
+			return "", fmt.Errorf("cannot build an absolute URL for route %v without a host", r.describe())
+		}
+		buf.WriteString(opts.Scheme + ":")
+	}
+	if host != "" {
+		buf.WriteString("//" + host)
+	}
+	buf.WriteString(path)
+	if len(opts.Query) > 0 {
+		buf.WriteString("?" + opts.Query.Encode())
+	}
+	if opts.Fragment != "" {
+		buf.WriteString("#" + (&url.URL{Fragment: opts.Fragment}).EscapedFragment())
+	}
+	return buf.String(), nil
+}
+
+// BuildURL builds the URL of the named route. See Route.BuildURL for details.
+func (r *Router) BuildURL(name string, params map[string]interface{}, options ...URLOptions) (string, error) {
+	route := r.Route(name)
+	if route == nil {
+		return "", fmt.Errorf("route %q not found", name)
+	}
+	return route.BuildURL(params, options...)
+}
+
+// buildPath builds the URL path of the route, choosing the variant of the route path whose parameters
+// are exactly the provided ones, apart from the host parameters.
+func (r *Route) buildPath(params map[string]interface{}) (string, error) {
+	provided := map[string]bool{}
+	for name := range params {
+		provided[name] = true
+	}
+	if h := r.group.host; h != nil {
+		for _, name := range h.pnames {
+			delete(provided, name)
+		}
+	}
+
+	var missing []string
+	for _, path := range r.Paths() {
+		names := paramNames(path)
+		if absent := missingNames(names, provided); len(absent) > 0 {
+			if missing == nil || len(absent) < len(missing) {
+				missing = absent
+			}
+			continue
+		} else if len(names) < len(provided) {
+			continue
+		}
+		return fillTokens(strings.TrimSuffix(path, "*"), params, "[^/]*", r.group.router.paramTypes)
+	}
+
+	names := paramNames(r.Path())
+	for name := range provided {
+		if !containsName(names, name) {
+			return "", fmt.Errorf("unknown parameter %q for route %v", name, r.describe())
+		}
+	}
+	if missing == nil {
+		// all parameters are known, but some of them belong to a segment that cannot be included
+		return "", fmt.Errorf("the parameters do not match the optional segments of route %v", r.describe())
+	}
+	return "", fmt.Errorf("missing parameter %q for route %v", missing[0], r.describe())
+}
+
+// fillTokens replaces the parameter tokens in a route or host pattern with the escaped parameter values,
+// after checking the values against the parameter patterns. The default pattern is used by the tokens
+// without a pattern, and the given parameter types are expanded into their regular expressions.
+func fillTokens(pattern string, params map[string]interface{}, defaultExpr string, ptypes map[string]*paramType) (string, error) {
+	var buf strings.Builder
+	last := 0
+	for _, m := range paramToken.FindAllStringSubmatchIndex(pattern, -1) {
+		buf.WriteString(pattern[last:m[0]])
+		last = m[1]
+
+		name, expr := pattern[m[2]:m[3]], defaultExpr
+		wildcard := strings.HasSuffix(name, "...")
+		if wildcard {
+			name, expr = strings.TrimSuffix(name, "..."), ".*"
+		} else if m[4] >= 0 {
+			expr = pattern[m[4]:m[5]]
+			if pt := ptypes[expr]; pt != nil {
+				expr = pt.regex
+			}
+		}
+		if name == "" {
+			return "", fmt.Errorf("cannot build a URL for the unnamed parameter in %q", pattern)
+		}
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("missing parameter %q for %q", name, pattern)
+		}
+		s := fmt.Sprint(value)
+		if regex := valueRegexp(expr); regex == nil || !regex.MatchString(s) {
+			return "", fmt.Errorf("value %q does not match parameter %q in %q", s, name, pattern)
+		}
+		if wildcard {
+			buf.WriteString(escapeSegments(s))
+		} else {
+			buf.WriteString(url.PathEscape(s))
+		}
+	}
+	buf.WriteString(pattern[last:])
+	return buf.String(), nil
+}
+
+// valueRegexps caches the regular expressions matching whole parameter values, indexed by parameter patterns.
+// An invalid pattern is cached as a nil regular expression.
+var valueRegexps sync.Map
+
+// valueRegexp returns the regular expression matching a whole value of the parameter pattern,
+// or nil if the pattern is invalid.
+func valueRegexp(expr string) *regexp.Regexp {
+	if regex, ok := valueRegexps.Load(expr); ok {
+		return regex.(*regexp.Regexp)
+	}
+	regex, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		regex = nil
+	}
+	valueRegexps.Store(expr, regex)
+	return regex
+}
+
+// paramNames returns the names of the parameters in a route path, including the named wildcard.
+func paramNames(path string) []string {
+	var names []string
+	for _, m := range paramToken.FindAllStringSubmatch(path, -1) {
+		if name := strings.TrimSuffix(m[1], "..."); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// missingNames returns the names that are not provided.
+func missingNames(names []string, provided map[string]bool) []string {
+	var missing []string
+	for _, name := range names {
+		if !provided[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
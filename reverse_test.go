@@ -0,0 +1,82 @@
+package neo
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteBuildURL(t *testing.T) {
+	router := New()
+	users := router.Group("/users").Get("/<id:int>[/<tab:[a-z]+>]").Name("user")
+	files := router.Get("/files/<path...>")
+	all := router.Get("/all/*")
+	router.Host("<tenant>.example.com").Get("/docs/<name>").Name("docs")
+
+	tests := []struct {
+		route    *Route
+		params   map[string]interface{}
+		opts     URLOptions
+		expected string
+		err      string
+	}{
+		{users, map[string]interface{}{"id": 12}, URLOptions{}, "/users/12", ""},
+		{users, map[string]interface{}{"id": 12, "tab": "posts"}, URLOptions{}, "/users/12/posts", ""},
+		{users, map[string]interface{}{"id": -3}, URLOptions{Query: url.Values{"sort": {"name"}, "a": {"1", "2"}}, Fragment: "top"}, "/users/-3?a=1&a=2&sort=name#top", ""},
+		{users, map[string]interface{}{"id": 12}, URLOptions{Scheme: "https", Host: "example.com"}, "https://example.com/users/12", ""},
+		{users, map[string]interface{}{"id": 12}, URLOptions{Host: "example.com"}, "//example.com/users/12", ""},
+		{users, map[string]interface{}{"id": 12}, URLOptions{Scheme: "https"}, "", `cannot build an absolute URL for route "GET /users/<id:int>[/<tab:[a-z]+>]" without a host`},
+		{users, map[string]interface{}{"id": "abc"}, URLOptions{}, "", `value "abc" does not match parameter "id" in "/users/<id:int>"`},
+		{users, map[string]interface{}{"id": 12, "tab": "A b"}, URLOptions{}, "", `value "A b" does not match parameter "tab" in "/users/<id:int>/<tab:[a-z]+>"`},
+		{users, map[string]interface{}{"tab": "posts"}, URLOptions{}, "", `missing parameter "id" for route "GET /users/<id:int>[/<tab:[a-z]+>]"`},
+		{users, nil, URLOptions{}, "", `missing parameter "id" for route "GET /users/<id:int>[/<tab:[a-z]+>]"`},
+		{users, map[string]interface{}{"id": 1, "page": 2}, URLOptions{}, "", `unknown parameter "page" for route "GET /users/<id:int>[/<tab:[a-z]+>]"`},
+		{files, map[string]interface{}{"path": "a b/c?.txt"}, URLOptions{}, "/files/a%20b/c%3F.txt", ""},
+		{all, nil, URLOptions{}, "/all/", ""},
+		{router.Route("docs"), map[string]interface{}{"tenant": "acme", "name": "x y"}, URLOptions{Scheme: "https"}, "https://acme.example.com/docs/x%20y", ""},
+		{router.Route("docs"), map[string]interface{}{"name": "x"}, URLOptions{}, "", `missing parameter "tenant" for "<tenant>.example.com"`},
+		{router.Route("docs"), map[string]interface{}{"tenant": "a.b", "name": "x"}, URLOptions{}, "", `value "a.b" does not match parameter "tenant" in "<tenant>.example.com"`},
+	}
+	for _, test := range tests {
+		actual, err := test.route.BuildURL(test.params, test.opts)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.route.String())
+		} else if assert.Nil(t, err, test.route.String()) {
+			assert.Equal(t, test.expected, actual, test.route.String())
+		}
+	}
+
+	u, err := router.BuildURL("user", map[string]interface{}{"id": 5})
+	assert.Nil(t, err)
+	assert.Equal(t, "/users/5", u)
+	_, err = router.BuildURL("unknown", nil)
+	assert.EqualError(t, err, `route "unknown" not found`)
+}
+
+func TestRouteBuildURLOptionalSegments(t *testing.T) {
+	router := New()
+	route := router.Get("/posts[/<page:\\d+>[/<size:\\d+>]]")
+
+	u, err := route.BuildURL(map[string]interface{}{"page": 2, "size": 10})
+	assert.Nil(t, err)
+	assert.Equal(t, "/posts/2/10", u)
+	_, err = route.BuildURL(map[string]interface{}{"size": 10})
+	assert.EqualError(t, err, `missing parameter "page" for route "GET /posts[/<page:\\d+>[/<size:\\d+>]]"`)
+
+	route = router.Get("/a[/<x>][/<y>]")
+	u, err = route.BuildURL(map[string]interface{}{"y": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "/a/1", u)
+}
+
+func TestValueRegexp(t *testing.T) {
+	regex := valueRegexp(`\d+`)
+	if assert.NotNil(t, regex) {
+		assert.True(t, regex.MatchString("12"))
+		assert.False(t, regex.MatchString("a12"))
+	}
+	assert.Same(t, regex, valueRegexp(`\d+`))
+	assert.Nil(t, valueRegexp(`[`))
+	assert.Nil(t, valueRegexp(`[`))
+}
@@ -7,6 +7,7 @@ package neo
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 )
 
@@ -17,16 +18,168 @@ type Route struct {
 	name, template        string
 	tags                  []interface{}
 	routes                []*Route
+	handlers              []Handler // the group handlers combined with the route handlers
+	names                 []string  // the names of handlers
+	groupHandlers         []Handler // the group handlers when the route was registered
+	groupNames            []string  // the names of groupHandlers
+	skipped               []string  // the names of the handlers skipped via Skip
+	matchers              []Matcher
+	meta                  *RouteMeta // the metadata returned by OptionsResponder
+	source                string     // the location ("file:line") where the route is registered
 }
 
 // Name sets the name of the route.
 // This method will update the registration of the route in the router as well.
 func (r *Route) Name(name string) *Route {
+	router := r.group.router
+	router.lock()
 	r.name = name
-	r.group.router.namedRoutes[name] = r
+	router.namedRoutes[name] = r
+	router.mu.Unlock()
 	return r
 }
 
+// RouteName returns the name of the route. An empty string is returned if the route is not named.
+func (r *Route) RouteName() string {
+	return r.name
+}
+
+// Replace replaces the handlers of the route with the given ones, which are combined with the group handlers
+// registered when the route was, ignoring the handlers added to the group afterwards. It is safe to call Replace while the router is serving requests.
+// Requests that are being handled continue with the old handlers.
+func (r *Route) Replace(handlers ...Handler) *Route {
+	if len(r.routes) > 0 {
+		// this route is a composite one (a path with multiple methods)
+		for _, route := range r.routes {
+			route.Replace(handlers...)
+		}
+		return r
+	}
+	router := r.group.router
+	router.lock()
+	r.handler = handlerName(handlers)
+	r.handlers = combineHandlers(r.groupHandlers, handlers)
+	r.names = combineNames(r.groupNames, handlerNames(handlers))
+	r.handlers, r.names = skipHandlers(r.handlers, r.names, r.skipped)
+	router.mu.Unlock()
+	return r
+}
+
+// Remove unregisters the route, or all the routes of a composite route returned by To.
+// The method reports whether any route has been removed.
+// It is safe to call Remove while the router is serving requests.
+func (r *Route) Remove() bool {
+	router := r.group.router
+	router.lock()
+	defer router.mu.Unlock()
+	if len(r.routes) > 0 {
+		return router.removeRoutes(func(route *Route) bool {
+			for _, rt := range r.routes {
+				if rt == route {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	return router.removeRoutes(func(route *Route) bool { return route == r })
+}
+
+// Paths returns the paths that the route matches, one for each combination of the optional segments
+// in the route path. For example, "/posts[/<page>]" results in "/posts" and "/posts/<page>".
+func (r *Route) Paths() []string {
+	return expandOptional(r.Path())
+}
+
+// storePath is a path of a route as it is added to the route store.
+type storePath struct {
+	path   string
+	ptypes []*paramType // the types of the path parameters, or nil if no parameter is typed
+}
+
+// storePaths returns the paths of the route as they are added to the route store.
+// The optional segments, named wildcards and parameter types are converted into plain parameter tokens.
+func (r *Route) storePaths() []storePath {
+	var paths []storePath
+	for _, path := range r.Paths() {
+		path = namedWildcard.ReplaceAllString(path, "<$1:.*>")
+		path, ptypes := r.group.router.expandParamTypes(path)
+		// an asterisk at the end matches any number of characters
+		if strings.HasSuffix(path, "*") {
+			path = path[:len(path)-1] + "<:.*>"
+		}
+		paths = append(paths, storePath{path, ptypes})
+	}
+	return paths
+}
+
+// entryKey returns the key of the routeEntry shared by the routes with the same host, method and store path.
+func (r *Route) entryKey(path string) string {
+	return r.Host() + " " + r.method + " " + path
+}
+
+// namedWildcard matches a named wildcard token, such as "<path...>", in a route path.
+var namedWildcard = regexp.MustCompile(`<([^:>]*)\.\.\.>`)
+
+// expandOptional returns the paths resulting from including or excluding each optional segment,
+// enclosed in square brackets, of the given path. Optional segments can be nested. Square brackets
+// in parameter tokens, such as "<id:[0-9]+>", are not optional segments.
+func expandOptional(path string) []string {
+	depth := 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case '[':
+			if depth > 0 {
+				continue
+			}
+			end := closingBracket(path, i)
+			if end < 0 {
+				return []string{path}
+			}
+			var paths []string
+			for _, rest := range expandOptional(path[end+1:]) {
+				paths = append(paths, path[:i]+rest)
+			}
+			for _, segment := range expandOptional(path[i+1 : end]) {
+				for _, rest := range expandOptional(path[end+1:]) {
+					paths = append(paths, path[:i]+segment+rest)
+				}
+			}
+			return paths
+		}
+	}
+	return []string{path}
+}
+
+// closingBracket returns the index of the square bracket closing the optional segment starting at the given index,
+// or -1 if the segment is not closed.
+func closingBracket(path string, start int) int {
+	depth, brackets := 0, 0
+	for i := start; i < len(path); i++ {
+		switch path[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case '[':
+			if depth == 0 {
+				brackets++
+			}
+		case ']':
+			if depth == 0 {
+				if brackets--; brackets == 0 {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
 // Tag associates some custom data with the route.
 func (r *Route) Tag(value interface{}) *Route {
 	if len(r.routes) > 0 {
@@ -43,6 +196,11 @@ func (r *Route) Tag(value interface{}) *Route {
 	return r
 }
 
+// Router returns the router that the route is registered to.
+func (r *Route) Router() *Router {
+	return r.group.router
+}
+
 // Method returns the HTTP method that this route is associated with.
 func (r *Route) Method() string {
 	return r.method
@@ -58,6 +216,14 @@ func (r *Route) Handler() string {
 	return r.handler
 }
 
+// Handlers returns the handlers of the route, including the ones inherited from its group.
+func (r *Route) Handlers() []Handler {
+	router := r.group.router
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	return r.handlers
+}
+
 // Tags returns all custom data associated with the route.
 func (r *Route) Tags() []interface{} {
 	return r.tags
@@ -117,18 +283,53 @@ func (r *Route) To(methods string, handlers ...Handler) *Route {
 // URL creates a URL using the current route and the given parameters.
 // The parameters should be given in the sequence of name1, value1, name2, value2, and so on.
 // If a parameter in the route is not provided a value, the parameter token will remain in the resulting URL.
-// The method will perform URL encoding for all given parameter values.
+// If the route is registered via Router.Host, the URL is a network-path reference including the host,
+// such as "//acme.example.com/users".
+// The method will perform URL encoding for all given parameter values as path segments. The value of a named wildcard,
+// such as "<path...>", is encoded segment by segment, keeping the slashes.
+// An optional segment, such as "[/<page>]", is left out if any of its parameters is not provided a value.
+// Use BuildURL to validate the parameter values and to add a query string.
 func (r *Route) URL(pairs ...interface{}) (s string) {
 	s = r.template
 	for i := 0; i < len(pairs); i++ {
-		name := fmt.Sprintf("<%v>", pairs[i])
+		name := fmt.Sprint(pairs[i])
 		value := ""
 		if i < len(pairs)-1 {
-			value = url.QueryEscape(fmt.Sprint(pairs[i+1]))
+			value = fmt.Sprint(pairs[i+1])
+		}
+		s = strings.Replace(s, "<"+name+">", url.PathEscape(value), -1)
+		s = strings.Replace(s, "<"+name+"...>", escapeSegments(value), -1)
+	}
+	return resolveOptional(s)
+}
+
+// escapeSegments performs URL encoding for each segment of a path separated by slashes.
+func escapeSegments(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// resolveOptional resolves the optional segments in a URL template, starting with the innermost ones.
+// A segment is removed if it still contains a parameter token. Otherwise, only its square brackets are removed.
+func resolveOptional(s string) string {
+	for {
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return s
+		}
+		start := strings.LastIndexByte(s[:end], '[')
+		if start < 0 {
+			return s
+		}
+		segment := s[start+1 : end]
+		if strings.IndexByte(segment, '<') >= 0 {
+			segment = ""
 		}
-		s = strings.Replace(s, name, value, -1)
+		s = s[:start] + segment + s[end+1:]
 	}
-	return
 }
 
 // String returns the string representation of the route.
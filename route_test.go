@@ -7,6 +7,8 @@ package neo
 import (
 	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,7 +24,8 @@ func newMockStore() *mockStore {
 }
 
 func (s *mockStore) Add(key string, data interface{}) int {
-	for _, handler := range data.([]Handler) {
+	routes := data.(*routeEntry).routes
+	for _, handler := range routes[len(routes)-1].handlers {
 		handler(nil)
 	}
 	return s.store.Add(key, data)
@@ -127,6 +130,26 @@ func TestRouteTag(t *testing.T) {
 	}
 }
 
+func TestRouteReplace(t *testing.T) {
+	var buf bytes.Buffer
+	router := New()
+	group := router.Group("/admin", newHandler("group.", &buf))
+	route := group.To("GET,POST", "/users", newHandler("old.", &buf))
+	// the handlers added to the group after the route are not combined with the replaced handlers
+	group.Use(newHandler("later.", &buf))
+	route.Replace(newHandler("new.", &buf))
+
+	for _, method := range []string{"GET", "POST"} {
+		buf.Reset()
+		req, _ := http.NewRequest(method, "/admin/users", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, "group.new.", buf.String(), method)
+	}
+
+	assert.True(t, route.Remove())
+	assert.Len(t, router.Routes(), 0)
+}
+
 func TestRouteMethods(t *testing.T) {
 	router := New()
 	for _, method := range Methods {
@@ -183,6 +206,8 @@ func TestBuildURLTemplate(t *testing.T) {
 		{"/users/<id:\\d+>/<test>/", "/users/<id>/<test>/"},
 		{"/users/<id:\\d+><test>", "/users/<id><test>"},
 		{"/users/<id:\\d+><test>/", "/users/<id><test>/"},
+		{"/files/<path...>", "/files/<path...>"},
+		{"/posts[/<page:[0-9]+>]", "/posts[/<page>]"},
 	}
 	for _, test := range tests {
 		actual := buildURLTemplate(test.path)
@@ -207,3 +232,63 @@ POST /users/<id>/profile
 POST /admin/users
 `, s)
 }
+
+func TestExpandOptional(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"/users", []string{"/users"}},
+		{"/posts[/<page:\\d+>]", []string{"/posts", "/posts/<page:\\d+>"}},
+		{"/users/<id:[0-9]+>", []string{"/users/<id:[0-9]+>"}},
+		{"/a[/b][/c]", []string{"/a", "/a/c", "/a/b", "/a/b/c"}},
+		{"/a[/b[/c]]", []string{"/a", "/a/b", "/a/b/c"}},
+		{"/a[/b", []string{"/a[/b"}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, expandOptional(test.path), test.path)
+	}
+}
+
+func TestRouteOptionalAndWildcard(t *testing.T) {
+	router := New()
+	files := router.Get("/files/<path...>", func(c *Context) error {
+		return c.Write(c.Param("path"))
+	})
+	posts := router.Get("/posts[/<page:\\d+>[/<size:int>]]", func(c *Context) error {
+		return c.Write("page=" + c.Param("page") + ",size=" + c.Param("size"))
+	})
+	assert.Equal(t, []string{"/posts", "/posts/<page:\\d+>", "/posts/<page:\\d+>/<size:int>"}, posts.Paths())
+
+	tests := []struct {
+		path, body string
+		status     int
+	}{
+		{"/files/a/b.txt", "a/b.txt", http.StatusOK},
+		{"/files/", "", http.StatusOK},
+		{"/posts", "page=,size=", http.StatusOK},
+		{"/posts/2", "page=2,size=", http.StatusOK},
+		{"/posts/2/10", "page=2,size=10", http.StatusOK},
+		{"/posts/x", "Not Found\n", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", test.path, nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.status, res.Code, test.path)
+		assert.Equal(t, test.body, res.Body.String(), test.path)
+	}
+
+	assert.Equal(t, "/files/a%20b/c.txt", files.URL("path", "a b/c.txt"))
+	assert.Equal(t, "/files/<path...>", files.URL())
+	assert.Equal(t, "/posts", posts.URL())
+	assert.Equal(t, "/posts/2", posts.URL("page", 2))
+	assert.Equal(t, "/posts/2/10", posts.URL("page", 2, "size", 10))
+	assert.Equal(t, "/posts", posts.URL("size", 10))
+
+	assert.True(t, router.Remove("GET", "/posts[/<page:\\d+>[/<size:int>]]"))
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/posts/2", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
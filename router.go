@@ -6,11 +6,12 @@
 package neo
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
-	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	radix "github.com/armon/go-radix"
 )
@@ -23,23 +24,62 @@ type (
 	Router struct {
 		RouteGroup
 		IgnoreTrailingSlash bool // whether to ignore trailing slashes in the end of the request URL
-		UseEscapedPath      bool // whether to use encoded URL instead of decoded URL to match routes
-		pool                sync.Pool
-		routes              []*Route
-		namedRoutes         map[string]*Route
-		stores              map[string]routeStore
-		maxParams           int
-		notFound            []Handler
-		notFoundHandlers    []Handler
+		// HandleHead makes GET routes serve HEAD requests, with the response body discarded, when no HEAD route
+		// matches the request. It is enabled by New.
+		HandleHead bool
+		// HandleMethodNotAllowed makes the router respond with http.StatusMethodNotAllowed and an Allow header
+		// when the request path matches some routes but none of them has the request method. It is enabled by New.
+		// If disabled, such requests are handled by the NotFound handlers.
+		HandleMethodNotAllowed bool
+		// HandleOptions makes the router respond to the OPTIONS requests whose path matches some routes but
+		// no OPTIONS route, via the handlers specified by RouteGroup.OptionsHandler. It is enabled by New.
+		HandleOptions bool
+		// CleanPath makes the router remove duplicate slashes and resolve "." and ".." segments in the request path
+		// before matching the routes, such as "/users/12" for "//users/./a/../12". A trailing slash is kept.
+		CleanPath bool
+		// CaseInsensitive makes the static parts of the route paths match the request path regardless of the case
+		// of their ASCII letters when no route matches the path exactly. Parameter values are not changed.
+		CaseInsensitive bool
+		// RedirectCanonicalPath makes the router redirect the requests whose path matches a route with the request
+		// method only after being cleaned (see CleanPath) or case-folded (see CaseInsensitive), instead of serving them.
+		// Requests whose canonical path only has routes with other methods are answered without redirection.
+		// GET and HEAD requests are redirected with http.StatusMovedPermanently, and the others with
+		// http.StatusPermanentRedirect. Trailing slashes can be redirected via slash.Remover.
+		RedirectCanonicalPath bool
+		Strict                bool // whether to panic with a RouteConflict when a conflicting route is added
+		UseEscapedPath        bool // whether to use encoded URL instead of decoded URL to match routes
+		pool                  sync.Pool
+		routes                []*Route
+		namedRoutes           map[string]*Route
+		stores                map[string]routeStore
+		maxParams             int
+		notFound              []Handler
+		notFoundHandlers      []Handler
+		notAllowedHandlers    []Handler
+		hosts                 []*hostRoutes
+		entries               map[string]*routeEntry
+		paramTypes            map[string]*paramType
+		mu                    sync.RWMutex // guards the route registrations against concurrent request handling
+		frozen                int32        // whether the routes can no longer be changed. accessed atomically.
+		allowMu               sync.Mutex   // serializes building the indexes while handling requests
+		indexes               atomic.Value // the *pathIndexes of the current routes, nil until needed
 
 		catchAll    *radix.Tree
 		IPExtractor IPExtractor
+
+		// Versioning configures how the API version of a request is selected for the routes registered via Version.
+		Versioning Versioning
+
+		// ErrorHandler handles the errors returned by the handlers and not handled otherwise.
+		// If nil, DefaultErrorHandler is used.
+		ErrorHandler func(*Context, error)
 	}
 
 	// routeStore stores route paths and the corresponding handlers.
 	routeStore interface {
 		Add(key string, data interface{}) int
 		Get(key string, pvalues []string) (data interface{}, pnames []string)
+		GetAfter(key string, pvalues []string, after int) (data interface{}, pnames []string, order int)
 		String() string
 	}
 )
@@ -62,11 +102,18 @@ func New() *Router {
 	r := &Router{
 		namedRoutes: make(map[string]*Route),
 		stores:      make(map[string]routeStore),
+		entries:     make(map[string]*routeEntry),
+		paramTypes:  builtinParamTypes(),
 		catchAll:    radix.New(),
 	}
 	r.RouteGroup = *newRouteGroup("", r, make([]Handler, 0))
-	r.NotFound(MethodNotAllowedHandler, NotFoundHandler)
+	r.HandleHead = true
+	r.HandleMethodNotAllowed = true
+	r.HandleOptions = true
+	r.NotFound(NotFoundHandler)
 	r.pool.New = func() interface{} {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
 		return &Context{
 			pvalues: make([]string, r.maxParams),
 			router:  r,
@@ -80,13 +127,24 @@ func New() *Router {
 func (r *Router) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	c := r.pool.Get().(*Context)
 	c.init(res, req)
+	frozen := r.Frozen()
+	if !frozen {
+		r.mu.RLock()
+	}
+	if len(c.pvalues) < r.maxParams {
+		// routes with more parameters have been added after the context was created
+		c.pvalues = make([]string, r.maxParams)
+	}
 	if r.UseEscapedPath {
-		c.handlers, c.pnames = r.find(req.Method, r.normalizeRequestPath(req.URL.EscapedPath()), c.pvalues)
+		r.route(c, r.normalizeRequestPath(req.URL.EscapedPath()))
 		for i, v := range c.pvalues {
 			c.pvalues[i], _ = url.QueryUnescape(v)
 		}
 	} else {
-		c.handlers, c.pnames = r.find(req.Method, r.normalizeRequestPath(req.URL.Path), c.pvalues)
+		r.route(c, r.normalizeRequestPath(req.URL.Path))
+	}
+	if !frozen {
+		r.mu.RUnlock()
 	}
 	if err := c.Next(); err != nil {
 		r.handleError(c, err)
@@ -94,78 +152,338 @@ func (r *Router) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	r.pool.Put(c)
 }
 
+// route sets the handlers and the parameters of the context for the request with the given path,
+// or the handlers redirecting the request to its canonical path if RedirectCanonicalPath is enabled.
+func (r *Router) route(c *Context, path string) {
+	requested := path
+	if r.CleanPath {
+		path = cleanPath(path)
+	}
+	handlers, pnames := r.lookup(c, path)
+	if handlers == nil && r.CaseInsensitive {
+		if folded := r.foldPath(c.Request.Host, path); folded != "" && folded != path {
+			path = folded
+			handlers, pnames = r.lookup(c, path)
+		}
+	}
+	if handlers != nil && c.allow == nil && path != requested && r.RedirectCanonicalPath && !strings.HasPrefix(path, "//") {
+		// a path starting with "//" would redirect to another host. A path without a route for the request
+		// method is not redirected, so that the 405 or OPTIONS response is sent directly.
+		handlers, pnames = r.redirectHandlers(path), nil
+	}
+	if handlers == nil {
+		handlers = r.notFoundHandlers
+	}
+	c.handlers, c.pnames, c.path = handlers, pnames, path
+}
+
+// lookup returns the handlers and the parameter names of the route matching the request with the given path,
+// or the handlers responding to a request whose path matches routes with other methods.
+// Nil handlers are returned if no route matches the path.
+func (r *Router) lookup(c *Context, path string) ([]Handler, []string) {
+	req := c.Request
+	handlers, pnames := r.match(c, req.Method, req.Host, path, c.pvalues)
+	if handlers == nil && req.Method == "HEAD" && r.HandleHead {
+		if handlers, pnames = r.match(c, "GET", req.Host, path, c.pvalues); handlers != nil {
+			c.resp.discard = true
+		}
+	}
+	if handlers == nil && (r.HandleMethodNotAllowed || r.HandleOptions) {
+		// the parameter values are overwritten while looking up the allowed methods
+		pnames = nil
+		if allow := r.allowedMethods(req.Host, path, c.pvalues); allow != nil && !allow.methods[req.Method] {
+			if req.Method == "OPTIONS" && r.HandleOptions {
+				c.allow = allow
+				handlers = allow.route.group.optionsHandlers()
+			} else if r.HandleMethodNotAllowed {
+				c.allow = allow
+				handlers = r.notAllowedHandlers
+			}
+		}
+	}
+	return handlers, pnames
+}
+
 // Route returns the named route.
 // Nil is returned if the named route cannot be found.
 func (r *Router) Route(name string) *Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.namedRoutes[name]
 }
 
 // Routes returns all routes managed by the router.
 func (r *Router) Routes() []*Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.routes
 }
 
+// Freeze makes the router an immutable snapshot of its routes. Once frozen, the router serves requests
+// without synchronization, and any attempt to change its routes or handlers results in a panic.
+// The route stores are compacted into a read-optimized form, which matches the parameters with regular
+// expressions several times faster.
+// Freeze returns the router itself so that it can be passed to a Switcher.
+func (r *Router) Freeze() *Router {
+	r.lock()
+	defer r.mu.Unlock()
+	compactStores(r.stores)
+	for _, h := range r.hosts {
+		compactStores(h.stores)
+	}
+	// the indexes are built before serving requests without synchronization
+	r.pathIndexes()
+	atomic.StoreInt32(&r.frozen, 1)
+	return r
+}
+
+// compactStores replaces the stores with their compacted versions.
+func compactStores(stores map[string]routeStore) {
+	for method, s := range stores {
+		if s, ok := s.(*store); ok {
+			stores[method] = s.compact()
+		}
+	}
+}
+
+// Frozen reports whether the router has been frozen via Freeze.
+func (r *Router) Frozen() bool {
+	return atomic.LoadInt32(&r.frozen) == 1
+}
+
+// lock acquires the lock for changing the routes. It panics if the router is frozen.
+func (r *Router) lock() {
+	r.mu.Lock()
+	if r.Frozen() {
+		r.mu.Unlock()
+		panic("neo: the routes of a frozen router cannot be changed")
+	}
+}
+
+// Remove unregisters the routes with the given HTTP method and path, excluding the routes registered via Host,
+// which can be removed via Route.Remove.
+// The path should be the full route path, including the prefix of the group that the route belongs to.
+// The method reports whether any route has been removed.
+// It is safe to call Remove while the router is serving requests.
+func (r *Router) Remove(method, path string) bool {
+	r.lock()
+	defer r.mu.Unlock()
+	return r.removeRoutes(func(route *Route) bool {
+		return route.group.host == nil && route.method == method && route.Path() == path
+	})
+}
+
+// removeRoutes unregisters the routes for which the function returns true, and reports whether any route
+// has been removed. The caller must hold the write lock of the router.
+func (r *Router) removeRoutes(remove func(*Route) bool) bool {
+	var (
+		routes  = make([]*Route, 0, len(r.routes))
+		removed []*Route
+	)
+	for _, route := range r.routes {
+		if remove(route) {
+			removed = append(removed, route)
+		} else {
+			routes = append(routes, route)
+		}
+	}
+	if len(removed) == 0 {
+		return false
+	}
+	r.routes = routes
+
+	for _, route := range removed {
+		if route.name != "" && r.namedRoutes[route.name] == route {
+			delete(r.namedRoutes, route.name)
+		}
+		for _, sp := range route.storePaths() {
+			key := route.entryKey(sp.path)
+			entry := r.entries[key]
+			for i, rt := range entry.routes {
+				if rt == route {
+					entry.routes = append(entry.routes[:i:i], entry.routes[i+1:]...)
+					break
+				}
+			}
+			if len(entry.routes) == 0 {
+				delete(r.entries, key)
+			}
+		}
+	}
+	for _, route := range removed {
+		r.rebuildStore(route.group.host, route.method)
+	}
+	r.resetAllowIndexes()
+	return true
+}
+
+// RemoveCatchAll unregisters the catch-all handlers registered via CatchAll with the given path prefix.
+// The method reports whether the handlers have been removed.
+// It is safe to call RemoveCatchAll while the router is serving requests.
+func (r *Router) RemoveCatchAll(prefix string) bool {
+	r.lock()
+	defer r.mu.Unlock()
+	_, ok := r.catchAll.Delete(prefix)
+	return ok
+}
+
+// rebuildStore recreates the store of the given host and method from the remaining routes
+// so that the removed paths no longer match.
+func (r *Router) rebuildStore(host *hostRoutes, method string) {
+	stores := r.stores
+	if host != nil {
+		stores = host.stores
+	}
+	store := newStore()
+	for _, route := range r.routes {
+		if route.group.host == host && route.method == method {
+			for _, sp := range route.storePaths() {
+				store.Add(sp.path, r.entries[route.entryKey(sp.path)])
+			}
+		}
+	}
+	stores[method] = store
+}
+
 // Use appends the specified handlers to the router and shares them with all routes.
 func (r *Router) Use(handlers ...Handler) {
+	r.lock()
+	defer r.mu.Unlock()
 	r.RouteGroup.Use(handlers...)
+	r.combineFallbackHandlers()
+}
+
+// combineFallbackHandlers combines the router handlers with the handlers used when no route matches a request.
+func (r *Router) combineFallbackHandlers() {
 	r.notFoundHandlers = combineHandlers(r.handlers, r.notFound)
+	r.notAllowedHandlers = combineHandlers(r.handlers, []Handler{methodNotAllowed})
 }
 
 // NotFound specifies the handlers that should be invoked when the router cannot find any route matching a request.
 // Note that the handlers registered via Use will be invoked first in this case.
 func (r *Router) NotFound(handlers ...Handler) {
+	r.lock()
+	defer r.mu.Unlock()
 	r.notFound = handlers
-	r.notFoundHandlers = combineHandlers(r.handlers, r.notFound)
+	r.combineFallbackHandlers()
 }
 
 // Find determines the handlers and parameters to use for a specified method and path.
+// Routes registered via Host are not considered, and route matchers are ignored.
 func (r *Router) Find(method, path string) (handlers []Handler, params map[string]string) {
-	pvalues := make([]string, r.maxParams)
-	handlers, pnames := r.find(method, path, pvalues)
+	// the parameter values are matched into the buffer of a pooled context
+	c := r.pool.Get().(*Context)
+	frozen := r.Frozen()
+	if !frozen {
+		r.mu.RLock()
+	}
+	if len(c.pvalues) < r.maxParams {
+		c.pvalues = make([]string, r.maxParams)
+	}
+	handlers, pnames := r.find(nil, method, "", path, c.pvalues)
+	if !frozen {
+		r.mu.RUnlock()
+	}
 	params = make(map[string]string, len(pnames))
 	for i, n := range pnames {
-		params[n] = pvalues[i]
+		params[n] = c.pvalues[i]
 	}
+	r.pool.Put(c)
 	return handlers, params
 }
 
 // handleError is the error handler for handling any unhandled errors.
 func (r *Router) handleError(c *Context, err error) {
-	if httpError, ok := err.(HTTPError); ok {
+	if r.ErrorHandler != nil {
+		r.ErrorHandler(c, err)
+		return
+	}
+	DefaultErrorHandler(c, err)
+}
+
+// DefaultErrorHandler is the error handler used by Router when Router.ErrorHandler is not set.
+//
+// If the response has already been written, the error is ignored since the status can no longer be changed.
+// A ProblemDetails found in the error chain is written via WriteProblem. An HTTPError found in the error chain
+// is written as plain text with its status code and message. Any other error results in
+// an http.StatusInternalServerError response whose body does not reveal the error message.
+func DefaultErrorHandler(c *Context, err error) {
+	if c.Written() {
+		return
+	}
+	var (
+		problem   *ProblemDetails
+		httpError HTTPError
+	)
+	switch {
+	case errors.As(err, &problem):
+		WriteProblem(c, problem)
+	case errors.As(err, &httpError):
 		http.Error(c.Response, httpError.Error(), httpError.StatusCode())
-	} else {
-		http.Error(c.Response, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(c.Response, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
 }
 
-func (r *Router) addRoute(route *Route, handlers []Handler) {
-	path := route.group.prefix + route.path
+func (r *Router) addRoute(route *Route) {
+	r.lock()
+	defer r.mu.Unlock()
 
+	if r.Strict {
+		if conflicts := findConflicts(route, r.routes); len(conflicts) > 0 {
+			panic(conflicts[0])
+		}
+	}
 	r.routes = append(r.routes, route)
+	r.resetAllowIndexes()
 
-	store := r.stores[route.method]
+	stores, hostParams := r.stores, 0
+	if host := route.group.host; host != nil {
+		stores, hostParams = host.stores, len(host.pnames)
+	}
+	store := stores[route.method]
 	if store == nil {
 		store = newStore()
-		r.stores[route.method] = store
+		stores[route.method] = store
 	}
 
-	// an asterisk at the end matches any number of characters
-	if strings.HasSuffix(path, "*") {
-		path = path[:len(path)-1] + "<:.*>"
+	for _, sp := range route.storePaths() {
+		// routes with the same method and path share the same entry in the store
+		key := route.entryKey(sp.path)
+		entry := r.entries[key]
+		if entry == nil {
+			entry = &routeEntry{ptypes: sp.ptypes}
+			r.entries[key] = entry
+		}
+		entry.routes = append(entry.routes, route)
+
+		if n := store.Add(sp.path, entry) + hostParams; n > r.maxParams {
+			r.maxParams = n
+		}
 	}
+}
 
-	if n := store.Add(path, handlers); n > r.maxParams {
-		r.maxParams = n
+func (r *Router) find(c *Context, method, host, path string, pvalues []string) (handlers []Handler, pnames []string) {
+	if handlers, pnames = r.match(c, method, host, path, pvalues); handlers == nil {
+		handlers = r.notFoundHandlers
 	}
+	return
 }
 
-func (r *Router) find(method, path string, pvalues []string) (handlers []Handler, pnames []string) {
-	var hh interface{}
-	if store := r.stores[method]; store != nil {
-		hh, pnames = store.Get(path, pvalues)
+// match finds the handlers of the route or the catch-all handlers matching the request of the context.
+// Nil handlers are returned if nothing matches the request. If the context is nil, the route matchers and
+// the parsers of the parameter types are ignored.
+func (r *Router) match(c *Context, method, host, path string, pvalues []string) (handlers []Handler, pnames []string) {
+	if len(r.hosts) > 0 && host != "" {
+		if handlers, pnames = r.findHost(c, method, host, path, pvalues); handlers != nil {
+			return
+		}
 	}
-	if hh != nil {
-		return hh.([]Handler), pnames
+
+	if store := r.stores[method]; store != nil {
+		if handlers, pnames = matchStore(c, store, path, pvalues); handlers != nil {
+			return
+		}
 	}
 
 	_, hh, ok := r.catchAll.LongestPrefix(path)
@@ -173,22 +491,23 @@ func (r *Router) find(method, path string, pvalues []string) (handlers []Handler
 		return hh.([]Handler), pnames
 	}
 
-	return r.notFoundHandlers, pnames
+	return nil, pnames
 }
 
-func (r *Router) findAllowedMethods(path string) map[string]bool {
+// findAllowedMethods returns the methods of the routes matching the host and the path.
+func (r *Router) findAllowedMethods(host, path string) map[string]bool {
 	methods := make(map[string]bool)
-	pvalues := make([]string, r.maxParams)
-	for m, store := range r.stores {
-		if handlers, _ := store.Get(path, pvalues); handlers != nil {
+	if allow := r.findAllowed(host, path); allow != nil {
+		for m := range allow.methods {
 			methods[m] = true
 		}
 	}
 	return methods
 }
 
+// FindAllowedMethods returns the methods of the routes matching the path, excluding the routes registered via Host.
 func (r *Router) FindAllowedMethods(path string) map[string]bool {
-	return r.findAllowedMethods(path)
+	return r.findAllowedMethods("", path)
 }
 
 func (r *Router) normalizeRequestPath(path string) string {
@@ -211,20 +530,17 @@ func NotFoundHandler(*Context) error {
 // MethodNotAllowedHandler handles the situation when a request has matching route without matching HTTP method.
 // In this case, the handler will respond with an Allow HTTP header listing the allowed HTTP methods.
 // Otherwise, the handler will do nothing and let the next handler (usually a NotFoundHandler) to handle the problem.
+//
+// Routers respond to such requests natively unless Router.HandleMethodNotAllowed is disabled, so the handler
+// is only needed as a NotFound handler of the routers where it is disabled.
 func MethodNotAllowedHandler(c *Context) error {
-	methods := c.Router().findAllowedMethods(c.Request.URL.Path)
-	if len(methods) == 0 {
+	router := c.Router()
+	allow := router.findAllowed(c.Request.Host, c.routePath())
+	if allow == nil || allow.methods[c.Request.Method] {
+		// no route matches the path, or the matching route rejected the request via its matchers
 		return nil
 	}
-	methods["OPTIONS"] = true
-	ms := make([]string, len(methods))
-	i := 0
-	for method := range methods {
-		ms[i] = method
-		i++
-	}
-	sort.Strings(ms)
-	c.Response.Header().Set("Allow", strings.Join(ms, ", "))
+	c.Response.Header().Set("Allow", allow.header)
 	if c.Request.Method != "OPTIONS" {
 		c.Response.WriteHeader(http.StatusMethodNotAllowed)
 	}
@@ -241,6 +557,7 @@ func HTTPHandlerFunc(h http.HandlerFunc) Handler {
 }
 
 // HTTPHandler adapts a http.Handler into a mat.Handler.
+// The handler is given the full request path. To serve a path prefix with the prefix stripped, see RouteGroup.Mount.
 func HTTPHandler(h http.Handler) Handler {
 	return func(c *Context) error {
 		h.ServeHTTP(c.Response, c.Request)
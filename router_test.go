@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,13 +31,13 @@ func TestRouterNotFound(t *testing.T) {
 	res = httptest.NewRecorder()
 	req, _ = http.NewRequest("PUT", "/users", nil)
 	r.ServeHTTP(res, req)
-	assert.Equal(t, "GET, OPTIONS, POST", res.Header().Get("Allow"), "Allow header")
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", res.Header().Get("Allow"), "Allow header")
 	assert.Equal(t, http.StatusMethodNotAllowed, res.Code, "HTTP status code")
 
 	res = httptest.NewRecorder()
 	req, _ = http.NewRequest("OPTIONS", "/users", nil)
 	r.ServeHTTP(res, req)
-	assert.Equal(t, "GET, OPTIONS, POST", res.Header().Get("Allow"), "Allow header")
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", res.Header().Get("Allow"), "Allow header")
 	assert.Equal(t, http.StatusOK, res.Code, "HTTP status code")
 
 	res = httptest.NewRecorder()
@@ -54,9 +56,10 @@ func TestRouterNotFound(t *testing.T) {
 
 func TestRouterUse(t *testing.T) {
 	r := New()
-	assert.Equal(t, 2, len(r.notFoundHandlers))
+	assert.Equal(t, 1, len(r.notFoundHandlers))
 	r.Use(NotFoundHandler)
-	assert.Equal(t, 3, len(r.notFoundHandlers))
+	assert.Equal(t, 2, len(r.notFoundHandlers))
+	assert.Equal(t, 2, len(r.notAllowedHandlers))
 }
 
 func TestRouterRoute(t *testing.T) {
@@ -81,6 +84,99 @@ func TestRouterFind(t *testing.T) {
 	if assert.Equal(t, 1, len(params)) {
 		assert.Equal(t, "1", params["id"])
 	}
+
+	r.add("GET", "/users", []Handler{NotFoundHandler})
+	handlers, params = r.Find("GET", "/users")
+	assert.Equal(t, 1, len(handlers))
+	assert.Equal(t, map[string]string{}, params)
+	r.Freeze()
+	_, params = r.Find("GET", "/users/2")
+	assert.Equal(t, map[string]string{"id": "2"}, params)
+}
+
+func TestRouterRemove(t *testing.T) {
+	r := New()
+	h := func(s string) Handler {
+		return func(c *Context) error {
+			return c.Write(s)
+		}
+	}
+	r.Get("/users/<id>", h("user")).Name("user")
+	r.Post("/users/<id>", h("update"))
+	r.Group("/admin").Get("/*", h("admin"))
+	hostRoute := r.Host("example.com").Get("/users/<id>", h("host"))
+	r.Group("/files").CatchAll(h("files"))
+
+	assert.True(t, r.Remove("GET", "/users/<id>"))
+	assert.False(t, r.Remove("GET", "/users/<id>"))
+	assert.True(t, r.Remove("GET", "/admin/*"))
+	assert.True(t, r.RemoveCatchAll("/files"))
+	assert.False(t, r.RemoveCatchAll("/files"))
+	assert.Nil(t, r.Route("user"))
+	// the routes registered via Host are not removed by path
+	assert.Len(t, r.Routes(), 2)
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "http://example.com/users/1", nil)
+	r.ServeHTTP(res, req)
+	assert.Equal(t, "host", res.Body.String())
+	assert.True(t, hostRoute.Remove())
+	assert.False(t, hostRoute.Remove())
+	assert.Len(t, r.Routes(), 1)
+
+	tests := []struct {
+		method, url, body string
+		status            int
+	}{
+		{"GET", "http://example.com/users/1", "Method Not Allowed\n", http.StatusMethodNotAllowed},
+		{"POST", "http://example.com/users/1", "update", http.StatusOK},
+		{"GET", "/admin/users", "Not Found\n", http.StatusNotFound},
+		{"GET", "/files/a.txt", "Not Found\n", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		r.ServeHTTP(res, req)
+		assert.Equal(t, test.status, res.Code, test.url)
+		if test.status != http.StatusMethodNotAllowed {
+			assert.Equal(t, test.body, res.Body.String(), test.url)
+		}
+	}
+
+	// re-adding a removed route
+	r.Get("/users/<id>", h("again"))
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/1", nil)
+	r.ServeHTTP(res, req)
+	assert.Equal(t, "again", res.Body.String())
+}
+
+func TestRouterConcurrentUpdate(t *testing.T) {
+	r := New()
+	h := func(c *Context) error {
+		return c.Write("ok")
+	}
+	users := r.Get("/users", h)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				res := httptest.NewRecorder()
+				req, _ := http.NewRequest("GET", "/users", nil)
+				r.ServeHTTP(res, req)
+				assert.Equal(t, http.StatusOK, res.Code)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		path := "/items/" + strconv.Itoa(i) + "/<a>/<b>/<c>"
+		r.Get(path, h).Name(path)
+		users.Replace(h)
+		r.Remove("GET", path)
+	}
+	wg.Wait()
 }
 
 func TestRouterNormalizeRequestPath(t *testing.T) {
@@ -113,6 +209,43 @@ func TestRouterHandleError(t *testing.T) {
 	c = &Context{Response: res}
 	r.handleError(c, NewHTTPError(http.StatusNotFound))
 	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	var handled error
+	r.ErrorHandler = func(c *Context, err error) {
+		handled = err
+	}
+	res = httptest.NewRecorder()
+	c = &Context{Response: res}
+	r.handleError(c, errors.New("abc"))
+	assert.Equal(t, "abc", handled.Error())
+}
+
+func TestDefaultErrorHandler(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/users", nil)
+	res := httptest.NewRecorder()
+	DefaultErrorHandler(NewContext(res, req), errors.New("secret"))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Equal(t, "Internal Server Error\n", res.Body.String())
+
+	res = httptest.NewRecorder()
+	DefaultErrorHandler(NewContext(res, req), fmt.Errorf("wrapped: %w", NewHTTPError(http.StatusConflict, "exists")))
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Equal(t, "exists\n", res.Body.String())
+
+	res = httptest.NewRecorder()
+	DefaultErrorHandler(NewContext(res, req), fmt.Errorf("wrapped: %w", NewProblem(http.StatusGone)))
+	assert.Equal(t, http.StatusGone, res.Code)
+	assert.Equal(t, MIME_PROBLEM_JSON, res.Header().Get("Content-Type"))
+
+	router := New()
+	router.Get("/users", func(c *Context) error {
+		c.WriteWithStatus("partial", http.StatusAccepted)
+		return errors.New("abc")
+	})
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusAccepted, res.Code)
+	assert.Equal(t, "partial", res.Body.String())
 }
 
 func TestHTTPHandler(t *testing.T) {
@@ -29,6 +29,7 @@ import (
 //
 // Note that Remover relies on HTTP redirection to remove the trailing slashes.
 // If you do not want redirection, please set `Router.IgnoreTrailingSlash` to be true without using Remover.
+// Other non-canonical paths, such as paths with duplicate slashes, can be redirected via `Router.RedirectCanonicalPath`.
 func Remover(status int) neo.Handler {
 	return func(c *neo.Context) error {
 		if c.Request.URL.Path != "/" && strings.HasSuffix(c.Request.URL.Path, "/") {
@@ -44,7 +44,18 @@ func (s *store) Add(key string, data interface{}) int {
 // If the data item was added to the store with a parametric key before, the matching
 // parameter names and values will be returned as well.
 func (s *store) Get(path string, pvalues []string) (data interface{}, pnames []string) {
-	data, pnames, _ = s.root.get(path, pvalues)
+	data, pnames, _ = s.root.get(path, pvalues, 0)
+	return
+}
+
+// GetAfter returns the data item matching the given concrete key that was added first after the data item
+// with the given order, together with its order. Get is GetAfter with order 0. Calling GetAfter with the order
+// it returned lists all data items matching the key, in the order they were added.
+func (s *store) GetAfter(path string, pvalues []string, after int) (data interface{}, pnames []string, order int) {
+	data, pnames, order = s.root.get(path, pvalues, after)
+	if data == nil {
+		order = 0
+	}
 	return
 }
 
@@ -220,7 +231,8 @@ func (n *node) addChild(key string, data interface{}, order int) int {
 }
 
 // get returns the data item with the key matching the tree rooted at the current node
-func (n *node) get(key string, pvalues []string) (data interface{}, pnames []string, order int) {
+// and the smallest order greater than after.
+func (n *node) get(key string, pvalues []string, after int) (data interface{}, pnames []string, order int) {
 	order = math.MaxInt32
 
 repeat:
@@ -272,9 +284,9 @@ repeat:
 				n = child
 				goto repeat
 			}
-			data, pnames, order = child.get(key, pvalues)
+			data, pnames, order = child.get(key, pvalues, after)
 		}
-	} else if n.data != nil {
+	} else if n.data != nil && n.order > after {
 		// do not return yet: a param node may match an empty string with smaller order
 		data, pnames, order = n.data, n.pnames, n.order
 	}
@@ -290,7 +302,7 @@ repeat:
 			tvalues = make([]string, len(pvalues))
 			allocated = true
 		}
-		if d, p, s := child.get(key, tvalues); d != nil && s < order {
+		if d, p, s := child.get(key, tvalues, after); d != nil && s < order {
 			if allocated {
 				for i := child.pindex; i < len(p); i++ {
 					pvalues[i] = tvalues[i]
@@ -162,3 +162,25 @@ func TestStoreGet(t *testing.T) {
 		assert.Equal(t, test.params, params, "store.Get("+test.key+").params =")
 	}
 }
+
+func TestStoreGetAfter(t *testing.T) {
+	s := newStore()
+	s.Add("/users/me", "me")
+	s.Add("/users/<id>", "user")
+	s.Add("/users/<id>/posts", "posts")
+	s.Add("/<path:.*>", "page")
+	stores := []routeStore{s, s.compact()}
+	for _, store := range stores {
+		pvalues := make([]string, 2)
+		var matched []string
+		for data, _, order := store.GetAfter("/users/me", pvalues, 0); data != nil; data, _, order = store.GetAfter("/users/me", pvalues, order) {
+			matched = append(matched, data.(string))
+		}
+		assert.Equal(t, []string{"me", "user", "page"}, matched)
+		data, pnames, order := store.GetAfter("/users/me", pvalues, 2)
+		assert.Equal(t, "page", data)
+		assert.Equal(t, []string{"path"}, pnames)
+		assert.Equal(t, "users/me", pvalues[0])
+		assert.Equal(t, 4, order)
+	}
+}
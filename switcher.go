@@ -0,0 +1,100 @@
+package neo
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Switcher is an http.Handler that dispatches requests to a Router which can be replaced atomically
+// while requests are being served. It is useful when the routes are rebuilt from a configuration
+// that changes at runtime.
+//
+// Routers given to Switcher are frozen via Router.Freeze, so they are immutable snapshots served
+// without synchronization. A request is always handled entirely by the router that was current
+// when the request started, using the Context pool of that router.
+//
+//	s := neo.NewSwitcher(buildRouter(config))
+//	go http.ListenAndServe(":8080", s)
+//	...
+//	<-s.Swap(buildRouter(newConfig)) // waits until the requests handled by the old router are completed
+type Switcher struct {
+	current atomic.Value // *snapshot
+	mu      sync.Mutex   // serializes swaps
+}
+
+// snapshot is a router served by Switcher together with the tracking of its in-flight requests.
+type snapshot struct {
+	router  *Router
+	active  int64         // the number of in-flight requests. accessed atomically.
+	retired int32         // whether the router has been replaced. accessed atomically.
+	drained chan struct{} // closed when the router is retired and has no in-flight requests
+	once    sync.Once
+}
+
+// NewSwitcher creates a Switcher serving requests with the given router.
+func NewSwitcher(router *Router) *Switcher {
+	s := &Switcher{}
+	s.current.Store(newSnapshot(router))
+	return s
+}
+
+func newSnapshot(router *Router) *snapshot {
+	if !router.Frozen() {
+		router.Freeze()
+	}
+	return &snapshot{
+		router:  router,
+		drained: make(chan struct{}),
+	}
+}
+
+// Router returns the router currently serving new requests.
+func (s *Switcher) Router() *Router {
+	return s.current.Load().(*snapshot).router
+}
+
+// Swap replaces the current router with the given one. New requests are handled by the new router immediately.
+// The returned channel is closed once all requests being handled by the old router are completed,
+// after which the old router and its pooled Contexts are no longer used.
+func (s *Switcher) Swap(router *Router) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.current.Load().(*snapshot)
+	s.current.Store(newSnapshot(router))
+	atomic.StoreInt32(&old.retired, 1)
+	if atomic.LoadInt64(&old.active) == 0 {
+		old.drain()
+	}
+	return old.drained
+}
+
+// ServeHTTP handles the request with the current router.
+func (s *Switcher) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	for {
+		snap := s.current.Load().(*snapshot)
+		atomic.AddInt64(&snap.active, 1)
+		if atomic.LoadInt32(&snap.retired) == 1 {
+			// the router has been replaced in the meantime and may have been reported as drained
+			snap.release()
+			continue
+		}
+		// released even if a handler panics, so that Swap does not wait for the request forever
+		defer snap.release()
+		snap.router.ServeHTTP(res, req)
+		return
+	}
+}
+
+// release marks an in-flight request of the snapshot as completed.
+func (s *snapshot) release() {
+	if atomic.AddInt64(&s.active, -1) == 0 && atomic.LoadInt32(&s.retired) == 1 {
+		s.drain()
+	}
+}
+
+func (s *snapshot) drain() {
+	s.once.Do(func() {
+		close(s.drained)
+	})
+}
@@ -0,0 +1,106 @@
+package neo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterFreeze(t *testing.T) {
+	router := New()
+	route := router.Get("/users", func(c *Context) error { return c.Write("users") })
+	assert.False(t, router.Frozen())
+	assert.Same(t, router, router.Freeze())
+	assert.True(t, router.Frozen())
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "users", res.Body.String())
+
+	assert.Panics(t, func() { router.Get("/posts") })
+	assert.Panics(t, func() { router.Remove("GET", "/users") })
+	assert.Panics(t, func() { route.Replace() })
+	assert.Panics(t, func() { route.Name("users") })
+	assert.Panics(t, func() { router.Use() })
+	assert.Panics(t, func() { router.Host("example.com") })
+	assert.Panics(t, func() { router.CatchAll() })
+}
+
+func TestSwitcher(t *testing.T) {
+	build := func(body string, started, release chan struct{}) *Router {
+		router := New()
+		router.Get("/", func(c *Context) error {
+			if started != nil {
+				close(started)
+				<-release
+			}
+			return c.Write(body)
+		})
+		return router
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	r1 := build("r1", started, release)
+	s := NewSwitcher(r1)
+	assert.True(t, r1.Frozen())
+	assert.Same(t, r1, s.Router())
+
+	// a request in flight on the first router
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		s.ServeHTTP(res, req)
+		assert.Equal(t, "r1", res.Body.String())
+	}()
+	<-started
+
+	drained := s.Swap(build("r2", nil, nil))
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	s.ServeHTTP(res, req)
+	assert.Equal(t, "r2", res.Body.String())
+
+	select {
+	case <-drained:
+		t.Fatal("the old router is drained while a request is in flight")
+	default:
+	}
+	close(release)
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("the old router is not drained")
+	}
+	wg.Wait()
+
+	// swapping an idle router drains it immediately
+	select {
+	case <-s.Swap(New()):
+	default:
+		t.Fatal("the idle router is not drained")
+	}
+}
+
+func TestSwitcherPanic(t *testing.T) {
+	router := New()
+	router.Get("/", func(c *Context) error { panic("boom") })
+	s := NewSwitcher(router)
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	assert.Panics(t, func() { s.ServeHTTP(res, req) })
+
+	select {
+	case <-s.Swap(New()):
+	case <-time.After(time.Second):
+		t.Fatal("the old router is not drained after a panicking request")
+	}
+}
@@ -0,0 +1,71 @@
+package neo
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+)
+
+// TypedFunc handles a request whose data has been bound into an input value of type In
+// and returns an output value of type Out to be written to the response.
+type TypedFunc[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Typed adapts a TypedFunc into a Handler.
+//
+// The returned handler binds the request data into a new input value before calling the function:
+// the request data is read the same way as Context.Read, including the fields tagged with `param`, `header`,
+// and `cookie`, then the fields tagged with `query:"name"` are populated with the URL query parameters.
+// The input type must be a struct or a pointer to a struct. A binding failure results in
+// an http.StatusBadRequest HTTPError. The bound input is then validated by Validate.
+//
+// The output value is written to the response via Context.Write so that the negotiated DataWriter is used.
+// The response status defaults to http.StatusOK and may be customized by the status parameter.
+// No body is written if the status is http.StatusNoContent.
+//
+//	r.Post("/users/<group>", neo.Typed(func(ctx context.Context, in CreateUser) (*User, error) {
+//	    return users.Create(ctx, in)
+//	}, http.StatusCreated))
+func Typed[In, Out any](fn TypedFunc[In, Out], status ...int) Handler {
+	code := http.StatusOK
+	if len(status) > 0 {
+		code = status[0]
+	}
+	isPtr := reflect.TypeOf((*In)(nil)).Elem().Kind() == reflect.Ptr
+
+	return func(c *Context) error {
+		var in In
+		data := interface{}(&in)
+		if isPtr {
+			rv := reflect.New(reflect.TypeOf(in).Elem())
+			reflect.ValueOf(&in).Elem().Set(rv)
+			data = in
+		}
+		if err := c.bind(data); err != nil {
+			return err
+		}
+
+		out, err := fn(c.Context(), in)
+		if err != nil {
+			return err
+		}
+		if code == http.StatusNoContent {
+			c.Response.WriteHeader(code)
+			return nil
+		}
+		return c.WriteWithStatus(out, code)
+	}
+}
+
+// bind populates the given struct pointer with the request body, the URL query parameters,
+// the route parameters, the request headers and cookies, and validates the result.
+func (c *Context) bind(data interface{}) error {
+	// a request without body is read as form data so that the URL query parameters are still used
+	reader := DefaultFormDataReader
+	if c.Request.ContentLength != 0 {
+		reader = c.reader()
+	}
+	if err := c.read(reader, data, true); err != nil {
+		return err
+	}
+	return Validate(data)
+}
@@ -0,0 +1,62 @@
+package neo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedInput struct {
+	ID     int      `param:"id"`
+	Tenant string   `header:"X-Tenant"`
+	Page   int      `query:"page"`
+	Sort   []string `query:"sort"`
+	Name   string   `json:"name"`
+}
+
+func TestTyped(t *testing.T) {
+	router := New()
+	router.Post(`/users/<id:\d+>`, Typed(func(ctx context.Context, in typedInput) (typedInput, error) {
+		return in, nil
+	}, http.StatusCreated))
+	router.Get(`/users/<id>`, Typed(func(ctx context.Context, in *typedInput) (string, error) {
+		if in.ID == 0 {
+			return "", errors.New("missing id")
+		}
+		return in.Tenant, nil
+	}))
+	router.Delete(`/users/<id>`, Typed(func(ctx context.Context, in struct{}) (interface{}, error) {
+		return "ignored", nil
+	}, http.StatusNoContent))
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/users/12?page=3&sort=a&sort=b", strings.NewReader(`{"name":"abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant", "acme")
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, "{12 acme 3 [a b] abc}", res.Body.String())
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/7", nil)
+	req.Header.Set("X-Tenant", "acme")
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "acme", res.Body.String())
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/abc", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/users/1", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "", res.Body.String())
+}
@@ -0,0 +1,424 @@
+package neo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const validateTag = "validate"
+
+// Validatable is implemented by types that perform their own validation.
+// Validate is called by the validation subsystem after the rules in the `validate` struct tags
+// have been checked. If it returns a *ValidationError, its field errors are merged into the result.
+// Any other error is reported as a violation of the value itself.
+type Validatable interface {
+	Validate() error
+}
+
+// FieldError describes a validation rule violated by a single field.
+type FieldError struct {
+	// Field is the path of the field using the json tag names, the form tag names or the field names,
+	// such as "address.city" or "items[0].name".
+	Field string `json:"field" xml:"field"`
+	// Rule is the name of the violated rule, such as "required" or "max".
+	Rule string `json:"rule" xml:"rule"`
+	// Message is the human-readable description of the violation.
+	Message string `json:"message" xml:"message"`
+}
+
+// ValidationError lists all field violations found when validating a value.
+// It is an HTTPError with the http.StatusUnprocessableEntity status.
+type ValidationError struct {
+	Errors []FieldError `json:"errors" xml:"errors>error"`
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		if fe.Field == "" {
+			msgs[i] = fe.Message
+		} else {
+			msgs[i] = fe.Field + ": " + fe.Message
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// StatusCode returns the HTTP status code.
+func (e *ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// Validate validates the given struct (or pointer to struct) according to the rules in the `validate` struct tags.
+// Multiple rules are separated by commas, for example `validate:"required,min=1,max=100"`. Supported rules are:
+//
+//   - required: the value must not be the zero value (or nil, or empty for strings, slices, and maps)
+//   - omitempty: the following rules are not checked if the value is empty in the sense of required
+//   - min=N, max=N: the number must be within the bound, or the length of a string, slice, or map must be
+//   - len=N: the length of a string, slice, or map must be exactly N
+//   - oneof=a b c: the value must be one of the space-separated values
+//   - email: the string must be a valid email address
+//   - regexp=PATTERN: the string must match the pattern. This rule must be the last one as the pattern may contain commas.
+//
+// Nested structs, pointers to structs, and slices of structs are validated recursively.
+// After the tag rules are checked, Validatable values are asked to validate themselves. The Validate method
+// of an embedded struct is only called via the outer struct, which promotes it unless it has its own.
+// Rules other than required are checked against zero values, such as 0 or "", unless omitempty precedes them,
+// but not against nil pointers and interfaces.
+//
+// Unknown rules, such as the rules of other validation packages, are ignored. The tags of a struct type are
+// parsed once. If a tag is invalid, such as "min=abc" or "min" given to a bool, an error other than
+// a *ValidationError is returned.
+//
+// Validate returns nil or a *ValidationError listing every violation.
+func Validate(data interface{}) error {
+	v := &validator{}
+	rv := reflect.ValueOf(data)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	v.validateValue("", rv, true)
+	if v.err != nil {
+		return v.err
+	}
+	if len(v.errors) > 0 {
+		return &ValidationError{Errors: v.errors}
+	}
+	return nil
+}
+
+type validator struct {
+	errors []FieldError
+	err    error // the error preventing the validation, such as an invalid validate tag
+}
+
+func (v *validator) add(path, rule, message string) {
+	v.errors = append(v.errors, FieldError{Field: path, Rule: rule, Message: message})
+}
+
+// validateValue validates nested values and calls Validatable.Validate on the value at the given path
+// if self is true.
+func (v *validator) validateValue(path string, rv reflect.Value, self bool) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		v.validateStruct(path, rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			v.validateValue(path+"["+strconv.Itoa(i)+"]", rv.Index(i), true)
+		}
+	}
+
+	if vv, ok := validatable(rv); ok && self {
+		v.addValidatableError(path, vv.Validate())
+	}
+}
+
+// validatable returns the value, or the pointer to it if it is addressable, as a Validatable.
+func validatable(rv reflect.Value) (Validatable, bool) {
+	var value interface{}
+	if rv.CanAddr() && rv.Addr().CanInterface() {
+		value = rv.Addr().Interface()
+	} else if rv.CanInterface() {
+		value = rv.Interface()
+	}
+	vv, ok := value.(Validatable)
+	return vv, ok
+}
+
+func (v *validator) addValidatableError(path string, err error) {
+	if err == nil {
+		return
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		v.add(path, "validate", err.Error())
+		return
+	}
+	for _, fe := range ve.Errors {
+		fe.Field = joinPath(path, fe.Field)
+		v.errors = append(v.errors, fe)
+	}
+}
+
+// validateStruct validates the fields of a struct. The Validate method of an embedded field is not called
+// if the struct has a Validate method, as it is either promoted from the field or shadows it.
+func (v *validator) validateStruct(path string, rv reflect.Value) {
+	info := getStructRules(rv.Type())
+	if info.err != nil {
+		if v.err == nil {
+			v.err = info.err
+		}
+		return
+	}
+	_, outer := validatable(rv)
+	for _, field := range info.fields {
+		fv := rv.Field(field.index)
+		fpath := path
+		if !field.anonymous {
+			fpath = joinPath(path, field.name)
+		}
+		v.checkRules(fpath, field.rules, fv)
+		v.validateValue(fpath, fv, !field.anonymous || !outer)
+	}
+}
+
+// structRules lists the fields of a struct type checked by Validate, with their parsed rules.
+type structRules struct {
+	fields []fieldRules
+	err    error // the error found in a validate tag, if any
+}
+
+// fieldRules describes a struct field checked by Validate.
+type fieldRules struct {
+	index     int
+	name      string
+	anonymous bool
+	rules     []rule
+}
+
+// rule is a validation rule parsed from a validate tag.
+type rule struct {
+	name    string
+	arg     string
+	bound   float64        // the argument of the min, max and len rules
+	options []string       // the argument of the oneof rule
+	regex   *regexp.Regexp // the argument of the regexp rule
+}
+
+var structRulesCache sync.Map // reflect.Type -> *structRules
+
+// getStructRules returns the rules of the fields of a struct type, parsing their validate tags once per type.
+func getStructRules(rt reflect.Type) *structRules {
+	if info, ok := structRulesCache.Load(rt); ok {
+		return info.(*structRules)
+	}
+	info := &structRules{}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.Anonymous && field.PkgPath != "" {
+			continue
+		}
+		fr := fieldRules{index: i, name: fieldName(field), anonymous: field.Anonymous}
+		if tag := field.Tag.Get(validateTag); tag != "" && tag != "-" {
+			rules, err := parseRules(tag, field.Type)
+			if err != nil {
+				info.err = fmt.Errorf("invalid validate tag of field %v.%v: %v", rt, field.Name, err)
+				break
+			}
+			fr.rules = rules
+		}
+		info.fields = append(info.fields, fr)
+	}
+	structRulesCache.Store(rt, info)
+	return info
+}
+
+// parseRules parses the rules of a validate tag given to a field of the given type.
+// Unknown rules, such as the rules of other validation packages, are ignored.
+func parseRules(tag string, rt reflect.Type) ([]rule, error) {
+	var rules []rule
+	for tag != "" {
+		s := tag
+		if strings.HasPrefix(tag, "regexp=") {
+			tag = ""
+		} else if i := strings.IndexByte(tag, ','); i >= 0 {
+			s, tag = tag[:i], tag[i+1:]
+		} else {
+			tag = ""
+		}
+
+		r := rule{name: s}
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			r.name, r.arg = s[:i], s[i+1:]
+		}
+		switch r.name {
+		case "required", "omitempty", "email":
+		case "min", "max", "len":
+			bound, err := strconv.ParseFloat(r.arg, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v rule argument: %q", r.name, r.arg)
+			}
+			if !hasBound(rt) {
+				return nil, fmt.Errorf("the %v rule cannot be applied to %v", r.name, rt)
+			}
+			r.bound = bound
+		case "oneof":
+			r.options = strings.Fields(r.arg)
+		case "regexp":
+			regex, err := regexp.Compile(r.arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp rule argument: %v", err)
+			}
+			r.regex = regex
+		default:
+			continue
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// hasBound reports whether the min, max and len rules can be applied to the values of the type.
+// The values of an interface type are checked when they are validated.
+func hasBound(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// fieldName returns the name identifying a struct field in a validation error path.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", formTag} {
+		name := field.Tag.Get(tag)
+		if i := strings.IndexByte(name, ','); i >= 0 {
+			name = name[:i]
+		}
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" || name == "" || name[0] == '[' {
+		return prefix + name
+	}
+	return prefix + "." + name
+}
+
+// checkRules checks the value of a field against the rules given in its validate tag.
+func (v *validator) checkRules(path string, rules []rule, rv reflect.Value) {
+	for _, r := range rules {
+		if r.name == "omitempty" {
+			if isEmptyValue(rv) {
+				return
+			}
+			continue
+		}
+		msg, err := checkRule(r, rv)
+		if err != nil {
+			if v.err == nil {
+				v.err = fmt.Errorf("cannot validate %v: %v", path, err)
+			}
+			return
+		}
+		if msg != "" {
+			v.add(path, r.name, msg)
+			if r.name == "required" {
+				return
+			}
+		}
+	}
+}
+
+// checkRule checks a single rule against the value and returns the violation message, if any.
+// An error is returned if the rule cannot be applied to the value.
+func checkRule(r rule, rv reflect.Value) (string, error) {
+	if r.name == "required" {
+		if isEmptyValue(rv) {
+			return "is required", nil
+		}
+		return "", nil
+	}
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			// a value that is not given is only checked by the required rule
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch r.name {
+	case "min", "max", "len":
+		value, isLength, ok := numericValue(rv)
+		if !ok {
+			return "", fmt.Errorf("the %v rule cannot be applied to %v", r.name, rv.Type())
+		}
+		switch {
+		case r.name == "min" && value < r.bound:
+			return boundMessage("must be at least", isLength, r.arg), nil
+		case r.name == "max" && value > r.bound:
+			return boundMessage("must be at most", isLength, r.arg), nil
+		case r.name == "len" && value != r.bound:
+			return boundMessage("must be exactly", isLength, r.arg), nil
+		}
+	case "oneof":
+		s := fmt.Sprint(rv.Interface())
+		for _, option := range r.options {
+			if s == option {
+				return "", nil
+			}
+		}
+		return "must be one of: " + strings.Join(r.options, ", "), nil
+	case "email":
+		if addr, err := mail.ParseAddress(rv.String()); err != nil || addr.Address != rv.String() {
+			return "must be a valid email address", nil
+		}
+	case "regexp":
+		if !r.regex.MatchString(rv.String()) {
+			return fmt.Sprintf("must match the pattern %q", r.arg), nil
+		}
+	}
+	return "", nil
+}
+
+// numericValue returns the value of a number or the length of a string, slice, or map.
+// It returns false if the value is of any other kind.
+func numericValue(rv reflect.Value) (value float64, isLength, ok bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), false, true
+	case reflect.String:
+		return float64(len([]rune(rv.String()))), true, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(rv.Len()), true, true
+	}
+	return 0, false, false
+}
+
+func boundMessage(prefix string, isLength bool, arg string) string {
+	if isLength {
+		return prefix + " " + arg + " in length"
+	}
+	return prefix + " " + arg
+}
+
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
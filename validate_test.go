@@ -0,0 +1,190 @@
+package neo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type vAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"regexp=^\\d{5}$"`
+}
+
+type vItem struct {
+	Name  string `form:"name" validate:"required,max=5"`
+	Count int    `validate:"min=1,max=10"`
+}
+
+type vUser struct {
+	Name    string    `json:"name" validate:"required,min=2"`
+	Email   string    `json:"email,omitempty" validate:"omitempty,email"`
+	Role    string    `json:"role" validate:"omitempty,oneof=admin user"`
+	Age     *int      `json:"age" validate:"required"`
+	Tags    []string  `json:"tags" validate:"omitempty,len=2"`
+	Address *vAddress `json:"address"`
+	Items   []vItem   `json:"items"`
+}
+
+func (u *vUser) Validate() error {
+	if u.Role == "admin" && u.Name != "root" {
+		return &ValidationError{Errors: []FieldError{{Field: "role", Rule: "admin", Message: "is reserved"}}}
+	}
+	return nil
+}
+
+type vSelf struct {
+	A int
+}
+
+func (s vSelf) Validate() error {
+	if s.A < 0 {
+		return errors.New("A must not be negative")
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	age := 10
+	assert.Nil(t, Validate(&vUser{Name: "abc", Age: &age}))
+	assert.Nil(t, Validate(vSelf{}))
+	assert.Nil(t, Validate((*vUser)(nil)))
+
+	err := Validate(&vUser{
+		Name:    "a",
+		Email:   "abc",
+		Role:    "admin",
+		Tags:    []string{"x"},
+		Address: &vAddress{Zip: "1234"},
+		Items:   []vItem{{Name: "abcdef", Count: 1}, {Name: "a", Count: 11}},
+	})
+	ve, ok := err.(*ValidationError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusUnprocessableEntity, ve.StatusCode())
+		assert.Equal(t, []FieldError{
+			{"name", "min", "must be at least 2 in length"},
+			{"email", "email", "must be a valid email address"},
+			{"age", "required", "is required"},
+			{"tags", "len", "must be exactly 2 in length"},
+			{"address.city", "required", "is required"},
+			{"address.zip", "regexp", `must match the pattern "^\\d{5}$"`},
+			{"items[0].name", "max", "must be at most 5 in length"},
+			{"items[1].Count", "max", "must be at most 10"},
+			{"role", "admin", "is reserved"},
+		}, ve.Errors)
+		assert.True(t, strings.HasPrefix(ve.Error(), "name: must be at least 2 in length; email: "))
+	}
+
+	err = Validate(&vUser{Name: "ab", Role: "guest", Age: &age})
+	assert.Equal(t, "role: must be one of: admin, user", err.Error())
+
+	// zero values are checked unless the rules are preceded by omitempty
+	type order struct {
+		Qty      int     `json:"qty" validate:"min=1"`
+		Priority int     `json:"priority" validate:"omitempty,min=1"`
+		Paid     bool    `json:"paid" validate:"oneof=true"`
+		Discount *int    `json:"discount" validate:"min=1"`
+		Code     *string `json:"code" validate:"omitempty,len=3"`
+	}
+	empty := ""
+	err = Validate(&order{Code: &empty})
+	// a pointer to an empty string is given, so it is not empty
+	assert.Equal(t, "qty: must be at least 1; paid: must be one of: true; code: must be exactly 3 in length", err.Error())
+	assert.Nil(t, Validate(&order{Qty: 1, Paid: true}))
+
+	err = Validate(&vSelf{A: -1})
+	assert.Equal(t, []FieldError{{"", "validate", "A must not be negative"}}, err.(*ValidationError).Errors)
+}
+
+type vBase struct {
+	ID int
+}
+
+func (b *vBase) Validate() error {
+	if b.ID == 0 {
+		return errors.New("base invalid")
+	}
+	return nil
+}
+
+type vEmbedded struct {
+	vBase
+	Name string `validate:"required"`
+}
+
+type vShadowed struct {
+	vBase
+}
+
+func (s *vShadowed) Validate() error {
+	return errors.New("shadowed")
+}
+
+func TestValidateEmbedded(t *testing.T) {
+	err := Validate(&vEmbedded{})
+	assert.Equal(t, "Name: is required; base invalid", err.Error())
+
+	err = Validate(&vShadowed{})
+	assert.Equal(t, "shadowed", err.Error())
+}
+
+func TestValidateTags(t *testing.T) {
+	type external struct {
+		Count int `validate:"gte=1,max=5"`
+	}
+	assert.Nil(t, Validate(&external{Count: 3}))
+	assert.Equal(t, "Count: must be at most 5", Validate(&external{Count: 6}).Error())
+
+	type badBound struct {
+		Count int `validate:"min=abc"`
+	}
+	err := Validate(&badBound{Count: 1})
+	assert.NotNil(t, err)
+	assert.Equal(t, `invalid validate tag of field neo.badBound.Count: invalid min rule argument: "abc"`, err.Error())
+
+	type badKind struct {
+		Flag bool `validate:"max=1"`
+	}
+	err = Validate(badKind{Flag: true})
+	assert.Equal(t, "invalid validate tag of field neo.badKind.Flag: the max rule cannot be applied to bool", err.Error())
+
+	type badRegexp struct {
+		Code string `validate:"regexp=["`
+	}
+	err = Validate(&badRegexp{})
+	assert.Contains(t, err.Error(), "invalid validate tag of field neo.badRegexp.Code: invalid regexp rule argument: ")
+
+	type dynamic struct {
+		Value interface{} `validate:"min=1"`
+	}
+	assert.Nil(t, Validate(&dynamic{Value: 2}))
+	err = Validate(&dynamic{Value: struct{ A int }{1}})
+	assert.Equal(t, "cannot validate Value: the min rule cannot be applied to struct { A int }", err.Error())
+
+	req, _ := http.NewRequest("POST", "/users", strings.NewReader(`{"Count":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	var data badBound
+	err = NewContext(nil, req).Read(&data)
+	assert.NotNil(t, err)
+	assert.False(t, errors.As(err, new(*ValidationError)))
+}
+
+func TestContextReadValidate(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/users", strings.NewReader(`{"name":"a","age":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	c := NewContext(nil, req)
+	var u vUser
+	err := c.Read(&u)
+	if assert.IsType(t, &ValidationError{}, err) {
+		assert.Equal(t, "name: must be at least 2 in length", err.Error())
+	}
+
+	req, _ = http.NewRequest("GET", "/users?name=abcdef&Count=3", nil)
+	c = NewContext(nil, req)
+	var item vItem
+	err = c.Read(&item)
+	assert.Equal(t, "name: must be at most 5 in length", err.Error())
+}
@@ -0,0 +1,200 @@
+package neo
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionStrategy specifies where the API version of a request is taken from.
+type VersionStrategy int
+
+const (
+	// VersionByPath selects the version by the path prefix, such as "/v2/users".
+	VersionByPath VersionStrategy = iota
+	// VersionByHeader selects the version by a request header, such as "Api-Version: 2".
+	VersionByHeader
+	// VersionByMediaType selects the version by a parameter of the media types in the "Accept" header,
+	// such as "Accept: application/vnd.example+json;version=2".
+	VersionByMediaType
+)
+
+// Versioning configures how Router selects the API version of a request for the routes registered via Version.
+type Versioning struct {
+	// Strategy specifies where the requested version is taken from. Defaults to VersionByPath.
+	Strategy VersionStrategy
+	// Header is the request header carrying the version when Strategy is VersionByHeader.
+	// Defaults to "Api-Version". The version serving the request is sent back in the same response header.
+	Header string
+	// Param is the media type parameter carrying the version when Strategy is VersionByMediaType.
+	// Defaults to "version".
+	Param string
+}
+
+// APIVersion describes a version of the API that routes belong to.
+type APIVersion struct {
+	// Name is the version name given to RouteGroup.Version, such as "v2" or "2.1".
+	Name string
+	// Deprecated indicates whether the version is deprecated.
+	Deprecated bool
+	// Sunset is the time when the version will be removed. Zero if unknown.
+	Sunset time.Time
+
+	major, minor int
+}
+
+// Version creates a RouteGroup whose routes belong to the named API version, such as "v2" or "2.1".
+//
+// How the version of a request is selected depends on Router.Versioning, which should be configured
+// before calling Version. With VersionByPath, the version name is appended to the group prefix, so "v2"
+// routes are served under "/v2". With VersionByHeader and VersionByMediaType, routes of different versions
+// share the same path, and a request is served by the route with the latest version compatible with the
+// requested one: the same major version and a minor version not greater than the requested one.
+// If the request does not specify a version, the latest version is used.
+// A route registered without version serves the requests for which no compatible version exists.
+//
+// Responses from the routes of a deprecated version carry the "Deprecation" and "Sunset" headers.
+// If no handler is provided, the new group will inherit the handlers registered with the current group.
+//
+//	r.Versioning = neo.Versioning{Strategy: neo.VersionByHeader}
+//	r.Version("1").Deprecate(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)).Get("/users", listUsersV1)
+//	r.Version("2").Get("/users", listUsersV2)
+func (rg *RouteGroup) Version(version string, handlers ...Handler) *RouteGroup {
+	v := &APIVersion{Name: version}
+	v.major, v.minor = parseVersion(version)
+
+	prefix := ""
+	if rg.router.Versioning.Strategy == VersionByPath {
+		prefix = "/" + version
+	}
+	g := rg.Group(prefix, handlers...)
+	g.version = v
+	g.UseNamed("version", versionHandler(rg.router, v))
+	return g
+}
+
+// Deprecate marks the API version of the group as deprecated.
+// The sunset time is optional and is sent in the "Sunset" header if given.
+// The method panics if the group is not created via Version.
+func (rg *RouteGroup) Deprecate(sunset ...time.Time) *RouteGroup {
+	if rg.version == nil {
+		panic("neo: Deprecate is called on a route group without version")
+	}
+	rg.version.Deprecated = true
+	if len(sunset) > 0 {
+		rg.version.Sunset = sunset[0]
+	}
+	return rg
+}
+
+// Version returns the API version that the route belongs to. Nil is returned if the route has no version.
+func (r *Route) Version() *APIVersion {
+	return r.group.version
+}
+
+// versionHandler returns a handler sending the version related headers of the response.
+// The Vary header lists the request header selecting the version, so that caches keep the responses
+// of different versions apart.
+func versionHandler(router *Router, v *APIVersion) Handler {
+	return func(c *Context) error {
+		header := c.Response.Header()
+		switch router.Versioning.Strategy {
+		case VersionByHeader:
+			header.Set(router.versionHeader(), v.Name)
+			addVary(header, router.versionHeader())
+		case VersionByMediaType:
+			addVary(header, HeaderAccept)
+		}
+		if v.Deprecated {
+			header.Set("Deprecation", "true")
+			if !v.Sunset.IsZero() {
+				header.Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+			}
+		}
+		return nil
+	}
+}
+
+// addVary adds the request header name to the Vary header unless it is listed already.
+func addVary(header http.Header, name string) {
+	for _, value := range header.Values(HeaderVary) {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v == "*" || strings.EqualFold(v, name) {
+				return
+			}
+		}
+	}
+	header.Add(HeaderVary, name)
+}
+
+func (r *Router) versionHeader() string {
+	if r.Versioning.Header == "" {
+		return "Api-Version"
+	}
+	return r.Versioning.Header
+}
+
+// requestedVersion returns the API version requested by the request, or an empty string if not specified.
+func (r *Router) requestedVersion(req *http.Request) string {
+	switch r.Versioning.Strategy {
+	case VersionByHeader:
+		return req.Header.Get(r.versionHeader())
+	case VersionByMediaType:
+		param := r.Versioning.Param
+		if param == "" {
+			param = "version"
+		}
+		for _, accept := range req.Header.Values("Accept") {
+			for _, mediaRange := range strings.Split(accept, ",") {
+				for _, p := range strings.Split(mediaRange, ";")[1:] {
+					if name, value, ok := strings.Cut(strings.TrimSpace(p), "="); ok && strings.EqualFold(name, param) {
+						return strings.Trim(value, `"`)
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// selectVersion returns the route with the latest version compatible with the version requested by the request.
+// Nil is returned if there is no compatible version.
+func selectVersion(req *http.Request, routes []*Route) *Route {
+	router := routes[0].group.router
+	if router.Versioning.Strategy == VersionByPath {
+		// the version has been selected by the path
+		return routes[0]
+	}
+	requested := router.requestedVersion(req)
+	major, minor := parseVersion(requested)
+
+	var selected *Route
+	for _, route := range routes {
+		v := route.group.version
+		if requested != "" && (v.major != major || minor >= 0 && v.minor > minor) {
+			continue
+		}
+		if selected == nil || v.newerThan(selected.group.version) {
+			selected = route
+		}
+	}
+	return selected
+}
+
+func (v *APIVersion) newerThan(other *APIVersion) bool {
+	return v.major > other.major || v.major == other.major && v.minor > other.minor
+}
+
+// parseVersion parses a version string such as "v2" or "2.1" into its major and minor numbers.
+// The minor number is -1 if it is not specified.
+func parseVersion(version string) (major, minor int) {
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	majorStr, minorStr, hasMinor := strings.Cut(version, ".")
+	major, _ = strconv.Atoi(majorStr)
+	minor = -1
+	if hasMinor {
+		minor, _ = strconv.Atoi(minorStr)
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package neo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		major, minor int
+	}{
+		{"v2", 2, -1},
+		{"2", 2, -1},
+		{"2.1", 2, 1},
+		{"V1.0", 1, 0},
+		{"", 0, -1},
+	}
+	for _, test := range tests {
+		major, minor := parseVersion(test.version)
+		assert.Equal(t, test.major, major, test.version)
+		assert.Equal(t, test.minor, minor, test.version)
+	}
+}
+
+func versionedRouter(versioning Versioning) *Router {
+	router := New()
+	router.Versioning = versioning
+	write := func(s string) Handler {
+		return func(c *Context) error {
+			return c.Write(s)
+		}
+	}
+	router.Version("1.0").Get("/users", write("1.0")).Name("users-v1.0")
+	router.Version("1.2").Get("/users", write("1.2"))
+	router.Version("2.0").Get("/users", write("2.0")).Name("users-v2.0")
+	router.Get("/users", write("none")).Name("users")
+	return router
+}
+
+func TestVersionByPath(t *testing.T) {
+	router := versionedRouter(Versioning{})
+
+	tests := []struct {
+		path, body string
+	}{
+		{"/1.0/users", "1.0"},
+		{"/1.2/users", "1.2"},
+		{"/2.0/users", "2.0"},
+		{"/users", "none"},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", test.path, nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.body, res.Body.String(), test.path)
+	}
+
+	assert.Equal(t, "/2.0/users", router.Route("users-v2.0").URL())
+	assert.Equal(t, "2.0", router.Route("users-v2.0").Version().Name)
+	assert.Nil(t, router.Route("users").Version())
+}
+
+func TestVersionByHeader(t *testing.T) {
+	router := versionedRouter(Versioning{Strategy: VersionByHeader, Header: "X-Version"})
+
+	tests := []struct {
+		version, body string
+	}{
+		{"1.0", "1.0"},
+		{"1.1", "1.0"},
+		{"1.5", "1.2"},
+		{"1", "1.2"},
+		{"v2", "2.0"},
+		{"", "2.0"},
+		{"3", "none"},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users", nil)
+		if test.version != "" {
+			req.Header.Set("X-Version", test.version)
+		}
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.body, res.Body.String(), test.version)
+		if test.body != "none" {
+			assert.Equal(t, test.body, res.Header().Get("X-Version"), test.version)
+			assert.Equal(t, []string{"X-Version"}, res.Header().Values("Vary"), test.version)
+		}
+	}
+	assert.Equal(t, "/users", router.Route("users-v2.0").URL())
+}
+
+func TestVersionByMediaType(t *testing.T) {
+	router := versionedRouter(Versioning{Strategy: VersionByMediaType})
+
+	tests := []struct {
+		accept, body string
+	}{
+		{"application/vnd.example+json;version=1.0", "1.0"},
+		{"text/html, application/vnd.example+json; version=\"1\"", "1.2"},
+		{"application/json", "2.0"},
+		{"application/vnd.example+json;version=4", "none"},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users", nil)
+		req.Header.Set("Accept", test.accept)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, test.body, res.Body.String(), test.accept)
+		if test.body != "none" {
+			assert.Equal(t, "Accept", res.Header().Get("Vary"), test.accept)
+		}
+	}
+
+	// no fallback route
+	router = New()
+	router.Versioning = Versioning{Strategy: VersionByMediaType}
+	router.Version("1").Get("/users", func(c *Context) error { return nil })
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users", nil)
+	req.Header.Set("Accept", "application/json;version=2")
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func TestVersionDeprecate(t *testing.T) {
+	router := New()
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	v1 := router.Version("v1").Deprecate(sunset)
+	v1.Get("/users", func(c *Context) error { return c.Write("v1") }).Name("v1")
+	router.Version("v2").Get("/users", func(c *Context) error { return c.Write("v2") })
+
+	assert.True(t, router.Route("v1").Version().Deprecated)
+	assert.Equal(t, sunset, router.Route("v1").Version().Sunset)
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "v1", res.Body.String())
+	assert.Equal(t, "true", res.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", res.Header().Get("Sunset"))
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v2/users", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "v2", res.Body.String())
+	assert.Empty(t, res.Header().Get("Deprecation"))
+	assert.Empty(t, res.Header().Get("Vary"))
+
+	assert.Panics(t, func() { router.Group("/api").Deprecate() })
+}
+
+func TestAddVary(t *testing.T) {
+	header := http.Header{}
+	addVary(header, "Accept")
+	addVary(header, "accept")
+	assert.Equal(t, []string{"Accept"}, header.Values("Vary"))
+	header.Set("Vary", "Origin, Accept-Encoding")
+	addVary(header, "Accept")
+	assert.Equal(t, []string{"Origin, Accept-Encoding", "Accept"}, header.Values("Vary"))
+	header.Set("Vary", "*")
+	addVary(header, "Accept")
+	assert.Equal(t, []string{"*"}, header.Values("Vary"))
+}